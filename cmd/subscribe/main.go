@@ -5,9 +5,11 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"syscall"
 
 	"github.com/patrickprogramme/subscribe/internal/app"
@@ -65,6 +67,19 @@ func main() {
 	if flags.Auto {
 		cfg.AutoMode = true
 	}
+	if flags.TranslateTo != "" {
+		cfg.TranslateTo = flags.TranslateTo
+	}
+	if flags.Speakers != "" {
+		names, err := config.ParseSpeakerNames(flags.Speakers)
+		if err != nil {
+			log.Fatalf("flag -speakers: %v", err)
+		}
+		if cfg.Speakers.Names == nil {
+			cfg.Speakers.Names = make(map[string]string)
+		}
+		maps.Copy(cfg.Speakers.Names, names)
+	}
 
 	// construction du renderer
 	renderer, err := obsidian.DefaultRenderer(exePath)
@@ -83,12 +98,66 @@ func main() {
 	}
 }
 
+// commands : sous-commandes reconnues en premier argument positionnel.
+var commands = []string{"tracks", "stats"}
+
+// parseFlags lit `subscribe [flags] [<url> | <commande> [args]] [flags]`.
+// Un premier argument qui n'est pas une commande connue est l'URL. Les flags
+// placés après la commande ou l'URL sont lus par leur propre FlagSet.
 func parseFlags() *app.CLIFlags {
-	f := &app.CLIFlags{}
-	flag.StringVar(&f.ConfigPath, "config", "subscribe.yaml", "path to config file")
-	flag.StringVar(&f.URL, "url", "", "YouTube URL (optional)")
-	flag.BoolVar(&f.Auto, "auto", false, "exécution automatique sans interaction")
-	flag.StringVar(&f.YtDlpPath, "yt-dlp-path", "", "chemin absolu vers l'exécutable yt-dlp")
+	f := &app.CLIFlags{ConfigPath: "subscribe.yaml"}
+	registerFlags(flag.CommandLine, f)
 	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		return f
+	}
+	name := args[0]
+	if slices.Contains(commands, name) {
+		f.Command = name
+	} else {
+		f.URL = name
+	}
+	// les valeurs déjà lues servent de défauts : un flag absent ne les écrase pas
+	sub := flag.NewFlagSet(name, flag.ExitOnError)
+	registerFlags(sub, f)
+	positional := parseInterleaved(sub, args[1:])
+	if f.Command != "" {
+		f.Args = positional
+	} else if len(positional) > 0 {
+		log.Fatalf("arguments inattendus après l'URL : %q", positional)
+	}
 	return f
 }
+
+// registerFlags déclare les flags globaux sur fs, avec les valeurs courantes de f pour défauts.
+func registerFlags(fs *flag.FlagSet, f *app.CLIFlags) {
+	fs.StringVar(&f.ConfigPath, "config", f.ConfigPath, "path to config file")
+	fs.StringVar(&f.URL, "url", f.URL, "YouTube URL (optional)")
+	fs.BoolVar(&f.Auto, "auto", f.Auto, "exécution automatique sans interaction")
+	fs.StringVar(&f.YtDlpPath, "yt-dlp-path", f.YtDlpPath, "chemin absolu vers l'exécutable yt-dlp")
+	fs.StringVar(&f.TranslateTo, "translate-to", f.TranslateTo, "langue cible (ex: en) : traduction automatique YouTube si besoin")
+	fs.StringVar(&f.Speakers, "speakers", f.Speakers, "noms des locuteurs (ex: S1=Alice,S2=Bob)")
+}
+
+// parseInterleaved lit les flags de args où qu'ils soient (flag s'arrête au premier
+// argument positionnel) et retourne les arguments positionnels, dans l'ordre.
+// Après "--", tout est positionnel.
+func parseInterleaved(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for len(args) > 0 {
+		_ = fs.Parse(args) // ExitOnError : une erreur quitte le programme
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		// fs.Parse a consommé "--" : le reste est positionnel
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	return positional
+}
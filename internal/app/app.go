@@ -2,16 +2,22 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/patrickprogramme/subscribe/internal/clipboard"
 	"github.com/patrickprogramme/subscribe/internal/config"
 	"github.com/patrickprogramme/subscribe/internal/fsutil"
+	"github.com/patrickprogramme/subscribe/internal/glossary"
+	"github.com/patrickprogramme/subscribe/internal/library"
 	"github.com/patrickprogramme/subscribe/internal/obsidian"
+	"github.com/patrickprogramme/subscribe/internal/sponsorblock"
+	"github.com/patrickprogramme/subscribe/internal/subtitles"
 	"github.com/patrickprogramme/subscribe/internal/ui"
 	"github.com/patrickprogramme/subscribe/internal/yt"
 	"github.com/patrickprogramme/subscribe/pkg/model"
@@ -19,9 +25,11 @@ import (
 
 const (
 	defaultUpdateTimeout  = 15 * time.Second
-	defaultExtractTimeout = 2 * time.Minute
-	dirPerm               = 0o755
-	filePerm              = 0o644
+	defaultExtractTimeout = 2 * time.Minute // par tentative yt-dlp (RetryPolicy.AttemptTimeout)
+	// SponsorBlock : API légère, un timeout court suffit
+	defaultSponsorBlockTimeout = 10 * time.Second
+	dirPerm                    = 0o755
+	filePerm                   = 0o644
 )
 
 // CLIFlags contient les information venant des flags de l'app
@@ -30,6 +38,14 @@ type CLIFlags struct {
 	URL        string
 	Auto       bool
 	YtDlpPath  string
+	// TranslateTo : langue cible de la traduction automatique (surcharge translate_to)
+	TranslateTo string
+	// Speakers : noms des locuteurs "S1=Alice,S2=Bob" (complète speakers.names)
+	Speakers string
+	// Command : premier argument positionnel ("" = génération de la note, "tracks" = liste
+	// des pistes, "stats" = statistiques de la bibliothèque)
+	Command string
+	Args    []string // arguments positionnels après Command
 }
 
 // App orchestre les différentes dépendances (UI, YtDlp, FS...)
@@ -52,20 +68,86 @@ func New(cfg *config.Config, uiClient ui.Interface, flags *CLIFlags, renderer *o
 	}
 }
 
-// Run exécute le flux principal. Il initialise ytClient (via InitYtDlp) en utilisant le ctx.
+// Run exécute la commande demandée. Il initialise ytClient (via InitYtDlp) en utilisant le ctx.
 // Ainsi l'initialisation respecte annulation/signaux.
 func (a *App) Run(ctx context.Context) error {
-	// Récupération de l'URL : priorité flag > clipboard > prompt
-	url := a.flags.URL
-	if url == "" {
-		// ui.GetYtURL effectue clipboard + prompt si nécessaire
-		u, err := a.ui.GetYtURL(ctx)
-		if err != nil {
-			return fmt.Errorf("get url: %w", err)
-		}
-		url = u
+	switch a.flags.Command {
+	case "":
+		return a.runNote(ctx)
+	case "tracks":
+		return a.runTracks(ctx)
+	case "stats":
+		return a.runStats(ctx)
+	default:
+		return fmt.Errorf("commande inconnue : %q (commandes : tracks, stats)", a.flags.Command)
 	}
+}
 
+// runTracks : `subscribe tracks <url>` liste les pistes de sous-titres disponibles.
+func (a *App) runTracks(ctx context.Context) error {
+	if len(a.flags.Args) > 0 {
+		a.flags.URL = a.flags.Args[0]
+	}
+	url, err := a.resolveURL(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.initYtDlp(ctx); err != nil {
+		return err
+	}
+	_, meta, err := a.extractMeta(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	var selected *subtitles.TrackSelection
+	src := a.subSource(meta)
+	tracks := meta.AutoSubs
+	if src == model.SubSourceManual {
+		tracks = meta.ManualSubs
+	}
+	if sel, ok := subtitles.ChooseTrack(meta, tracks, a.selectOptions()); ok {
+		selected = &sel
+	}
+	a.ui.PrintInfo(ctx, fmt.Sprintf("%q\n%s", meta.Title, TracksReport(meta, selected)))
+	return nil
+}
+
+// runStats : `subscribe stats [titre]` résume la bibliothèque (output_dir), ou
+// détaille les vidéos dont le titre contient l'argument.
+func (a *App) runStats(ctx context.Context) error {
+	corpus := a.loadLibrary(ctx)
+	if len(a.flags.Args) == 0 {
+		a.ui.PrintInfo(ctx, LibraryReport(corpus, a.cfg.Stats.Keywords))
+		return nil
+	}
+	query := strings.Join(a.flags.Args, " ")
+	docs := library.Find(corpus, query)
+	if len(docs) == 0 {
+		return fmt.Errorf("aucune vidéo de la bibliothèque ne correspond à %q", query)
+	}
+	for _, d := range docs {
+		d.Keywords = library.Keywords(d, corpus, a.cfg.Stats.Keywords)
+		a.ui.PrintInfo(ctx, StatsReport(d))
+	}
+	return nil
+}
+
+// resolveURL : priorité flag/argument > clipboard > prompt
+func (a *App) resolveURL(ctx context.Context) (string, error) {
+	if a.flags.URL != "" {
+		return a.flags.URL, nil
+	}
+	// ui.GetYtURL effectue clipboard + prompt si nécessaire
+	u, err := a.ui.GetYtURL(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get url: %w", err)
+	}
+	return u, nil
+}
+
+// initYtDlp initialise ytClient (CheckBinary + version) et lance l'update check si configuré.
+func (a *App) initYtDlp(ctx context.Context) error {
 	// si l'utilisateur a passé --yt-dlp-path, l'appliquer et re-resoudre
 	if a.flags.YtDlpPath != "" {
 		// on peut assigner dans cfg le champ Path (valeur brute), puis
@@ -74,8 +156,13 @@ func (a *App) Run(ctx context.Context) error {
 		// a.cfg.EnsureYtDlpResolved()
 	}
 
-	// Init yt-dlp (CheckBinary + version)
-	dl, version, err := yt.InitYtDlp(ctx, a.cfg)
+	// progression en direct : les warnings/erreurs sont résumés après l'extraction
+	onMessage := func(m yt.Message) {
+		if m.Level < yt.LevelWarning {
+			a.ui.PrintProgress(ctx, m.String())
+		}
+	}
+	dl, version, err := yt.InitYtDlp(ctx, a.cfg, onMessage)
 	if err != nil {
 		return fmt.Errorf("yt init: %w", err)
 	}
@@ -85,27 +172,156 @@ func (a *App) Run(ctx context.Context) error {
 	if a.cfg.YtDlp.AutoUpdateCheck {
 		a.YtDlpUpdateCheck(ctx, defaultUpdateTimeout, version)
 	}
+	return nil
+}
 
-	// Extraction des métadonnées
-	exCtx, exCancel := context.WithTimeout(ctx, defaultExtractTimeout)
-	defer exCancel()
-
-	raw, err := a.ytClient.ExtractRaw(exCtx, url)
+// extractMeta lance yt-dlp (avec retry) et parse les métadonnées de la première entrée.
+func (a *App) extractMeta(ctx context.Context, url string) (*yt.ExtractedRaw, *model.Meta, error) {
+	raw, err := yt.ExtractRawWithRetry(ctx, a.ytClient, url, a.retryPolicy(),
+		func(attempt int, delay time.Duration, xerr *yt.ExtractError) {
+			a.ui.PrintError(ctx, fmt.Sprintf("⚠️  %v\nNouvelle tentative (%d/%d) dans %s...",
+				xerr, attempt, a.cfg.YtDlp.Retry.MaxAttempts, delay))
+		})
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
-			return fmt.Errorf("opération annulée")
+			return nil, nil, fmt.Errorf("opération annulée")
 		}
-		return fmt.Errorf("extract raw: %w", err)
+		var xerr *yt.ExtractError
+		if errors.As(err, &xerr) {
+			a.ui.PrintError(ctx, "💡 "+xerr.Remedy())
+		}
+		return nil, nil, fmt.Errorf("extract raw: %w", err)
 	}
 	raw.PrintWarnings()
+	if len(raw.Entries) > 1 {
+		a.ui.PrintInfo(ctx, fmt.Sprintf("ℹ️  yt-dlp a renvoyé %d entrées, seule la première est traitée.", len(raw.Entries)))
+	}
 
-	// parse métadonnées
 	meta, err := yt.ParseYTDLP(raw.JSON)
 	if err != nil {
-		return fmt.Errorf("parse ytdlp: %w", err)
+		return nil, nil, fmt.Errorf("parse ytdlp: %w", err)
+	}
+	return raw, meta, nil
+}
+
+// subSource : manuels si préférés et disponibles, sinon automatiques.
+func (a *App) subSource(meta *model.Meta) model.SubSource {
+	if a.cfg.PreferManualSubs && meta.HasManualSubs() {
+		return model.SubSourceManual
+	}
+	return model.SubSourceAutomatic
+}
+
+// selectOptions : préférences de langue et traduction depuis la config.
+func (a *App) selectOptions() subtitles.SelectOptions {
+	return subtitles.SelectOptions{
+		Preferred:   a.cfg.PreferredLanguages,
+		TranslateTo: a.cfg.TranslateTo,
+	}
+}
+
+// subtitleMaxBytes : taille maximale d'une piste téléchargée (max_subtitle_mb).
+func (a *App) subtitleMaxBytes() int64 {
+	return int64(a.cfg.MaxSubtitleMB) << 20
+}
+
+// phraseOptions : découpe en phrases depuis la config (la langue vient de la piste).
+func (a *App) phraseOptions() subtitles.Options {
+	abbrevs := make(map[string]struct{}, len(a.cfg.Sentences.Abbreviations))
+	for _, ab := range a.cfg.Sentences.Abbreviations {
+		abbrevs[ab] = struct{}{}
+	}
+	return subtitles.Options{
+		BreakOnAbbreviations: a.cfg.Sentences.BreakOnAbbreviations,
+		Abbreviations:        abbrevs,
+		MergeShortPhrases:    a.cfg.Sentences.MergeShortPhrases,
+		MinPhraseWordsToKeep: a.cfg.Sentences.MinPhraseWords,
+		Speakers:             a.cfg.Speakers.Enabled,
+	}
+}
+
+// cleanupOptions : nettoyage du bruit ASR depuis la config.
+func (a *App) cleanupOptions(lang string) subtitles.CleanupOptions {
+	c := a.cfg.Cleanup
+	return subtitles.CleanupOptions{
+		Lang:         lang,
+		Strict:       c.Strict,
+		SoundTags:    c.SoundTags,
+		Censored:     c.Censored,
+		Stutters:     c.Stutters,
+		Fillers:      c.Fillers,
+		ExtraFillers: c.ExtraFillers,
+	}
+}
+
+// applyGlossary corrige le transcript avec les glossaires de la chaîne et de la
+// langue, affiche chaque remplacement et retourne les termes connus (prompt).
+func (a *App) applyGlossary(ctx context.Context, meta *model.Meta, tr subtitles.Transcript) (subtitles.Transcript, []string, error) {
+	g, err := glossary.Load(a.cfg.GlossaryFiles())
+	if err != nil {
+		return tr, nil, err
+	}
+	sel := g.For(meta.Uploader, tr.Track.Lang)
+	var report subtitles.GlossaryReport
+	tr.Phrases, report = subtitles.ApplyGlossary(tr.Phrases, sel.Replacements, tr.Track.Lang)
+	if len(report.Hits) > 0 {
+		a.ui.PrintInfo(ctx, fmt.Sprintf("Glossaire : %d remplacements : %s", len(report.Hits), report))
+	}
+	return tr, sel.Terms, nil
+}
+
+// restorePunctuation ponctue et met en casse les captions auto qui en sont dépourvues
+// (restore_punctuation.mode), noms propres tirés du titre, de la description et des tags.
+func (a *App) restorePunctuation(ctx context.Context, meta *model.Meta, tr subtitles.Transcript) (subtitles.Transcript, error) {
+	mode, err := subtitles.ParseRestoreMode(a.cfg.RestorePunctuation.Mode)
+	if err != nil {
+		return tr, err
+	}
+	if mode == subtitles.RestoreOff || tr.Track.Source != model.SubSourceAutomatic {
+		return tr, nil
+	}
+	if mode == subtitles.RestoreAuto && !subtitles.NeedsRestoration(tr.Phrases) {
+		return tr, nil
+	}
+	before := len(tr.Phrases)
+	tr.Phrases = subtitles.RestorePunctuation(tr.Phrases, subtitles.RestoreOptions{
+		Lang:        tr.Track.Lang,
+		ProperNouns: subtitles.ProperNounLexicon(meta.Title, meta.Description, strings.Join(meta.YtTags, "\n")),
+		PauseFactor: a.cfg.RestorePunctuation.PauseFactor,
+		MinWords:    a.cfg.RestorePunctuation.MinWords,
+		MaxWords:    a.cfg.RestorePunctuation.MaxWords,
+	})
+	a.ui.PrintInfo(ctx, fmt.Sprintf("Ponctuation restaurée : %d -> %d phrases.", before, len(tr.Phrases)))
+	return tr, nil
+}
+
+// runNote exécute le flux principal : extraction, transcript, prompt IA, note Obsidian.
+func (a *App) runNote(ctx context.Context) error {
+	url, err := a.resolveURL(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.initYtDlp(ctx); err != nil {
+		return err
+	}
+
+	// Extraction + parse des métadonnées
+	raw, meta, err := a.extractMeta(ctx, url)
+	if err != nil {
+		return err
 	}
 	a.ui.PrintInfo(ctx, meta.Pretty())
 
+	// segments SponsorBlock (optionnel, non bloquant)
+	if a.cfg.SponsorBlock.Enabled {
+		segs, err := sponsorblock.FetchSegments(ctx, a.cfg.SponsorBlock.APIURL, meta.ID,
+			a.cfg.SponsorBlock.Categories, defaultSponsorBlockTimeout)
+		if err != nil {
+			a.ui.PrintError(ctx, fmt.Sprintf("SponsorBlock indisponible, transcript non filtré : %v", err))
+		}
+		meta.Segments = segs
+	}
+
 	// préparation dossier de sortie + sauvegardes
 	outDir := a.cfg.OutputDir
 	if a.cfg.SaveInSubdir {
@@ -127,17 +343,15 @@ func (a *App) Run(ctx context.Context) error {
 	}
 
 	// téléchargement des sous-titre
-	var subsSource model.SubSource
-	if a.cfg.PreferManualSubs && meta.HasManualSubs() {
-		subsSource = model.SubSourceManual
-	} else {
-		subsSource = model.SubSourceAutomatic
-	}
-
-	subsDownloaded, err := FetchSubtitleDownload(ctx, meta, subsSource)
+	subsSource := a.subSource(meta)
+	subsDownloaded, err := FetchSubtitleDownload(ctx, meta, subsSource, a.selectOptions(), a.subtitleMaxBytes(), a.cfg.SaveRawSubs)
 	if err != nil {
 		return err
 	}
+	if subsDownloaded.Track.Lang != "" {
+		sel := subtitles.TrackSelection{Track: subsDownloaded.Track, Reason: subsDownloaded.Reason}
+		a.ui.PrintInfo(ctx, fmt.Sprintf("Sous-titres retenus : %s, %s", sel, subsDownloaded.Track.Source))
+	}
 
 	if a.cfg.SaveRawSubs {
 		if err := SaveSubtitleDownload(subsDownloaded, outDir); err != nil {
@@ -145,24 +359,122 @@ func (a *App) Run(ctx context.Context) error {
 		}
 	}
 	// Création du transcript + sauvegarde
-	transcript, err := BuildTranscriptFromSubtitleDownload(&subsDownloaded, meta)
+	transcript, err := BuildTranscriptFromSubtitleDownload(&subsDownloaded, meta, a.phraseOptions())
 	if err != nil {
 		return err
 	}
-	if a.cfg.SaveTranscript {
-		tFormat, err := model.ParseFormat(a.cfg.TranscriptFormat)
+	snapMode, err := subtitles.ParseChapterSnapMode(a.cfg.ChapterSnap.Mode)
+	if err != nil {
+		return err
+	}
+	transcript.ChapterSnap = subtitles.ChapterSnap{Mode: snapMode, MaxMs: int64(a.cfg.ChapterSnap.MaxSec) * 1000}
+	var asr *model.ASRQuality
+	if a.cfg.ASRCheck.Enabled {
+		if asr, err = a.checkASR(ctx, meta, subsDownloaded, outDir); err != nil {
+			return err
+		}
+	}
+	if transcript, err = a.restorePunctuation(ctx, meta, transcript); err != nil {
+		return err
+	}
+	if a.cfg.Cleanup.Enabled {
+		var report subtitles.CleanupReport
+		transcript.Phrases, report = subtitles.CleanPhrases(transcript.Phrases, a.cleanupOptions(transcript.Track.Lang))
+		if report.Total() > 0 || report.Dropped > 0 {
+			a.ui.PrintInfo(ctx, "Nettoyage du transcript : "+report.String())
+		}
+	}
+	var knownTerms []string
+	if a.cfg.Glossary.Enabled {
+		if transcript, knownTerms, err = a.applyGlossary(ctx, meta, transcript); err != nil {
+			return err
+		}
+	}
+	if a.cfg.Speakers.Enabled {
+		if speakers := transcript.Speakers(); len(speakers) > 0 {
+			a.ui.PrintInfo(ctx, "Locuteurs détectés : "+strings.Join(speakers, ", "))
+		}
+		transcript = transcript.WithSpeakerNames(a.cfg.Speakers.Names)
+	}
+	if len(meta.Segments) > 0 {
+		mode, err := subtitles.ParseSegmentMode(a.cfg.SponsorBlock.Mode)
 		if err != nil {
 			return err
 		}
-		if err := SaveTranscript(transcript, tFormat, outDir); err != nil {
-			return fmt.Errorf("échec de la sauvegarde du transcript: %w", err)
+		var report subtitles.SegmentReport
+		transcript.Phrases, report = subtitles.ApplySegments(transcript.Phrases, meta.Segments, mode)
+		verb := "retirées"
+		if mode == subtitles.SegmentModeTag {
+			verb = "marquées"
+		}
+		msg := fmt.Sprintf("SponsorBlock : %d segments (%s), %d phrases %s",
+			len(meta.Segments), model.Seconds(model.TotalSegmentsMs(meta.Segments)/1000).Human(),
+			report.Phrases, verb)
+		if report.Clipped > 0 {
+			msg += fmt.Sprintf(", %d écourtées", report.Clipped)
+		}
+		a.ui.PrintInfo(ctx, msg+".")
+	}
+	if a.cfg.AutoChapters.Enabled && len(transcript.Chapters) == 0 {
+		// les chapitres générés suivent ensuite le chemin des chapitres de la vidéo
+		transcript.Chapters = subtitles.GenerateChapters(transcript.Phrases, subtitles.ChapterOptions{
+			Lang:         transcript.Track.Lang,
+			MinChapterMs: int64(a.cfg.AutoChapters.MinChapterSec) * 1000,
+			PauseMs:      a.cfg.AutoChapters.PauseMs,
+			TitleTerms:   a.cfg.AutoChapters.TitleWords,
+			StopWords:    a.cfg.Stats.StopWords,
+		})
+		if len(transcript.Chapters) > 0 {
+			meta.Chapters = transcript.Chapters
+			a.ui.PrintInfo(ctx, fmt.Sprintf("Chapitres générés depuis le transcript : %d", len(transcript.Chapters)))
+		}
+	}
+	if !a.cfg.WordTimings {
+		transcript = transcript.WithoutWords()
+	}
+	if a.cfg.Paragraphs.Enabled {
+		transcript.ParagraphOpts = &subtitles.ParagraphOptions{
+			PauseMs:     a.cfg.Paragraphs.PauseMs,
+			TargetWords: a.cfg.Paragraphs.TargetWords,
+			MaxWords:    a.cfg.Paragraphs.MaxWords,
+			Markers:     a.cfg.Paragraphs.Markers,
+		}
+	}
+	var stats *model.TranscriptStats
+	if a.cfg.Stats.Enabled {
+		if stats, err = a.saveStats(ctx, meta, transcript, outDir); err != nil {
+			return err
+		}
+	}
+	if a.cfg.SaveTranscript {
+		for _, f := range a.cfg.TranscriptFormats {
+			tFormat, err := model.ParseFormat(f)
+			if err != nil {
+				return err
+			}
+			if err := SaveTranscript(transcript, tFormat, outDir); err != nil {
+				return fmt.Errorf("échec de la sauvegarde du transcript: %w", err)
+			}
+		}
+	}
+
+	if a.cfg.Bilingual.Enabled {
+		if err := a.saveBilingual(ctx, meta, transcript, outDir); err != nil {
+			return err
 		}
 	}
 
 	var summary string
 	if a.cfg.GenerateAIPrompt {
 		// génération du prompt + copie dans le presse-papier.
-		fullPrompt, err := BuildFullChatPrompt(transcript, a.cfg.PromptSplitThreshold)
+		promptOpts := PromptOptions{SplitThreshold: a.cfg.PromptSplitThreshold}
+		if a.cfg.Comments.InPrompt > 0 {
+			promptOpts.Comments = model.TopComments(meta.Comments, a.cfg.Comments.InPrompt)
+		}
+		if a.cfg.Glossary.InPrompt {
+			promptOpts.KnownTerms = knownTerms
+		}
+		fullPrompt, err := BuildFullChatPrompt(transcript, promptOpts)
 		if err != nil {
 			if errors.Is(err, ErrPromptTooLong) {
 				fmt.Println("⚠️  Le prompt dépasse la limite, attention à la taille totale.")
@@ -194,6 +506,18 @@ func (a *App) Run(ctx context.Context) error {
 
 	// Création de la note
 	noteData := obsidian.NewNoteData(meta, summary)
+	noteData.SubtitleLang = transcript.Track.Lang
+	noteData.SubtitleSource = string(transcript.Track.Source)
+	noteData.SubtitleReason = string(transcript.Reason)
+	noteData.SubtitleTranslated = transcript.Track.Translated
+	noteData.SubtitleSourceLang = transcript.Track.SourceLang
+	noteData.ASR = asr
+	noteData.Stats = stats
+	if a.cfg.Heatmap.KeyMoments > 0 && len(meta.Heatmap) > 0 {
+		peaks := model.HeatmapPeaks(meta.Heatmap, a.cfg.Heatmap.KeyMoments,
+			int64(a.cfg.Heatmap.MinGapSec)*1000)
+		noteData.KeyMoments = transcript.KeyMoments(peaks)
+	}
 	var vaultDir string
 	if a.cfg.ObsidianVaultDir == "" || a.cfg.ObsidianVaultDir == "." {
 		vaultDir = outDir
@@ -201,6 +525,15 @@ func (a *App) Run(ctx context.Context) error {
 		vaultDir = a.cfg.ObsidianVaultDir
 	}
 
+	if a.cfg.SplitNotes.Enabled && SplitNotes(noteData.Chapters, meta.Duration, a.cfg.SplitNotes.MinChapters, a.cfg.SplitNotes.MinDurationMin) {
+		outPath, err := a.saveSplitNotes(vaultDir, noteData, transcript)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Note parente et %d notes de chapitre écrites dans le répertoire:\n%s\n", len(noteData.Chapters), outPath)
+		return a.ui.WaitForExit(ctx)
+	}
+
 	content, err := a.renderer.Render("obsidian_note.md.tmpl", noteData)
 	if err != nil {
 		return fmt.Errorf("render error: %v", err)
@@ -215,3 +548,123 @@ func (a *App) Run(ctx context.Context) error {
 	// Attendre terminaison (Entrée OU Ctrl+C) via UI
 	return a.ui.WaitForExit(ctx)
 }
+
+// saveSplitNotes écrit une note par chapitre puis la note parente qui les liste
+// (split_notes). Retourne le chemin de la note parente.
+func (a *App) saveSplitNotes(vaultDir string, parent obsidian.NoteData, tr subtitles.Transcript) (string, error) {
+	chapters := obsidian.SplitChapters(&parent, ChapterTexts(tr))
+	for _, c := range chapters {
+		content, err := a.renderer.Render(a.cfg.SplitNotes.ChapterTemplate, c)
+		if err != nil {
+			return "", fmt.Errorf("render error: %v", err)
+		}
+		if _, err := fsutil.SaveMarkdownAtomic(vaultDir, c.Filename, content, true); err != nil {
+			return "", fmt.Errorf("cannot save file to disk: %v", err)
+		}
+	}
+	content, err := a.renderer.Render(a.cfg.SplitNotes.ParentTemplate, parent)
+	if err != nil {
+		return "", fmt.Errorf("render error: %v", err)
+	}
+	outPath, err := fsutil.SaveMarkdownAtomic(vaultDir, parent.Filename, content, true)
+	if err != nil {
+		return "", fmt.Errorf("cannot save file to disk: %v", err)
+	}
+	return outPath, nil
+}
+
+// retryPolicy construit la politique de retry de yt-dlp depuis la config.
+func (a *App) retryPolicy() yt.RetryPolicy {
+	p := yt.DefaultRetryPolicy()
+	p.MaxAttempts = a.cfg.YtDlp.Retry.MaxAttempts
+	p.InitialDelay = time.Duration(a.cfg.YtDlp.Retry.InitialDelaySec) * time.Second
+	p.MaxDelay = time.Duration(a.cfg.YtDlp.Retry.MaxDelaySec) * time.Second
+	p.AttemptTimeout = defaultExtractTimeout
+	return p
+}
+
+// checkASR compare captions auto et sous-titres manuels (asr_check), affiche le WER
+// et écrit le rapport dans outDir. Sans l'une des deux sources, un avertissement
+// est affiché et nil est retourné.
+func (a *App) checkASR(ctx context.Context, meta *model.Meta, sd subtitles.SubtitleDownload, outDir string) (*model.ASRQuality, error) {
+	clean := subtitles.DefaultCleanupOptions()
+	clean.Lang = sd.Track.Lang
+	clean.ExtraFillers = a.cfg.Cleanup.ExtraFillers
+	cmp, err := CompareWithCounterpart(ctx, meta, sd, a.phraseOptions(), &clean, a.subtitleMaxBytes())
+	if err != nil {
+		if errors.Is(err, subtitles.ErrNoSubtitle) {
+			a.ui.PrintError(ctx, fmt.Sprintf("Pas de sous-titres manuels et de captions auto en %q à comparer, rapport ASR non écrit.", sd.Track.Lang))
+			return nil, nil
+		}
+		return nil, err
+	}
+	q := cmp.Quality
+	a.ui.PrintInfo(ctx, fmt.Sprintf("Captions auto : WER %s, %s.", q.WERPercent(), q.Verdict()))
+	path := filepath.Join(outDir, ASRReportFilename(meta.Title, q.Lang))
+	if err := fsutil.WriteFileAtomic(path, []byte(cmp.Markdown(meta.Title, meta.WatchURL())), filePerm); err != nil {
+		return nil, fmt.Errorf("write asr report %s: %w", path, err)
+	}
+	a.ui.PrintInfo(ctx, "Rapport de comparaison écrit : "+path)
+	return &q, nil
+}
+
+// loadLibrary lit la bibliothèque (output_dir). Un fichier de statistiques
+// corrompu n'est pas fatal : il est signalé et ignoré, comme un dossier illisible.
+func (a *App) loadLibrary(ctx context.Context) []model.TranscriptStats {
+	corpus, err := library.Load(a.cfg.OutputDir)
+	if err != nil {
+		a.ui.PrintError(ctx, fmt.Sprintf("Bibliothèque incomplète, fichiers ignorés : %v", err))
+	}
+	return corpus
+}
+
+// saveStats calcule les statistiques du transcript, ses mots-clés face à la
+// bibliothèque (output_dir) et les écrit dans outDir.
+func (a *App) saveStats(ctx context.Context, meta *model.Meta, tr subtitles.Transcript, outDir string) (*model.TranscriptStats, error) {
+	s := tr.Stats(subtitles.StatsOptions{
+		GapMs:     int64(a.cfg.Stats.GapSec) * 1000,
+		TopTerms:  a.cfg.Stats.TopTerms,
+		StopWords: a.cfg.Stats.StopWords,
+	})
+	s.VideoID = meta.ID
+	s.Keywords = library.Keywords(s, a.loadLibrary(ctx), a.cfg.Stats.Keywords)
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encodage des statistiques: %w", err)
+	}
+	path := filepath.Join(outDir, library.StatsFilename(tr.Title))
+	if err := fsutil.WriteFileAtomic(path, data, filePerm); err != nil {
+		return nil, fmt.Errorf("write stats %s: %w", path, err)
+	}
+	a.ui.PrintInfo(ctx, fmt.Sprintf("Statistiques : %d mots, %s mots/min, lecture %s.",
+		s.Words, formatWPM(s.WPM), s.ReadingTime()))
+	return &s, nil
+}
+
+// saveBilingual écrit le transcript bilingue dans outDir. L'absence de seconde
+// piste n'est pas fatale : un avertissement est affiché.
+func (a *App) saveBilingual(ctx context.Context, meta *model.Meta, tr subtitles.Transcript, outDir string) error {
+	layout, err := subtitles.ParseBilingualLayout(a.cfg.Bilingual.Layout)
+	if err != nil {
+		return err
+	}
+	segMode, err := subtitles.ParseSegmentMode(a.cfg.SponsorBlock.Mode)
+	if err != nil {
+		return err
+	}
+	doc, err := BuildBilingual(ctx, meta, tr, a.cfg.Bilingual.Lang, layout, segMode, a.phraseOptions(), a.subtitleMaxBytes())
+	if err != nil {
+		if errors.Is(err, subtitles.ErrNoSubtitle) {
+			a.ui.PrintError(ctx, fmt.Sprintf("Pas de piste %q pour le transcript bilingue, fichier non écrit.", a.cfg.Bilingual.Lang))
+			return nil
+		}
+		return err
+	}
+	path := filepath.Join(outDir, BilingualFilename(tr.Title, tr.Track.Lang, a.cfg.Bilingual.Lang))
+	if err := fsutil.WriteFileAtomic(path, doc, filePerm); err != nil {
+		return fmt.Errorf("write bilingual %s: %w", path, err)
+	}
+	a.ui.PrintInfo(ctx, "Transcript bilingue écrit : "+path)
+	return nil
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/patrickprogramme/subscribe/internal/fsutil"
 	"github.com/patrickprogramme/subscribe/internal/ia"
+	"github.com/patrickprogramme/subscribe/internal/library"
 	"github.com/patrickprogramme/subscribe/internal/subtitles"
 	"github.com/patrickprogramme/subscribe/internal/ui"
 	"github.com/patrickprogramme/subscribe/internal/updater"
@@ -19,14 +21,19 @@ import (
 
 var ErrPromptTooLong = errors.New("prompt dépasse le seuil autorisé")
 
+// subtitleTimeout : délai de téléchargement d'une piste de sous-titres, décodage
+// json3 compris ; jusqu'à max_subtitle_mb (50 Mo) pour les lives de plusieurs heures.
+const subtitleTimeout = 30 * time.Second
+
 // FetchSubtitleDownload télécharge la piste de sous-titres pour la meta `m` et la source `ss`.
+// La langue est choisie par subtitles.ChooseTrack selon `opts` ; maxBytes borne la taille du fichier.
+// keepData garde les octets bruts (save_raw_subs) en plus des events json3 décodés en flux.
 func FetchSubtitleDownload(
-	ctx context.Context, m *model.Meta, ss model.SubSource) (subtitles.SubtitleDownload, error) {
+	ctx context.Context, m *model.Meta, ss model.SubSource, opts subtitles.SelectOptions, maxBytes int64, keepData bool) (subtitles.SubtitleDownload, error) {
 	var empty subtitles.SubtitleDownload
-	maxBytes := int64(10_000_000)
-	timeout := 15 * time.Second
+	timeout := subtitleTimeout
 
-	sd, err := subtitles.DownloadSubtitleFromMeta(ctx, m, ss, timeout, maxBytes)
+	sd, err := subtitles.DownloadSubtitleFromMeta(ctx, m, ss, opts, timeout, maxBytes, keepData)
 	if err != nil {
 		// s'il n'y a pas de sous-titres, ce n'est pas une erreur fatale...
 		if errors.Is(err, subtitles.ErrNoSubtitle) {
@@ -68,30 +75,142 @@ func SaveSubtitleDownload(sd subtitles.SubtitleDownload, outDir string) error {
 	return nil
 }
 
+// BuildTranscriptFromSubtitleDownload parse la piste (sd.ParseRaw) et découpe en phrases selon opts
+// (opts.Lang vaut la langue de la piste si vide).
 func BuildTranscriptFromSubtitleDownload(
-	sd *subtitles.SubtitleDownload, m *model.Meta) (subtitles.Transcript, error) {
+	sd *subtitles.SubtitleDownload, m *model.Meta, opts subtitles.Options) (subtitles.Transcript, error) {
 	var empty subtitles.Transcript
 
 	if sd == nil {
 		return empty, fmt.Errorf("BuildTranscript: SubtitleDownload est nil")
 	}
 
-	// parse JSON3
-	raw, err := sd.ParseRawJSON3()
+	// parse (json3, srv3, vtt, ttml ou srt selon la piste)
+	raw, err := sd.ParseRaw()
 	if err != nil {
-		return empty, fmt.Errorf("parse raw json3: %w", err)
+		return empty, fmt.Errorf("parse raw %s: %w", sd.Track.Format, err)
 	}
 
-	// phrases, err := subtitles.TransformAutoRawToPhrases(raw)
-	phrases, err := subtitles.TransformRawToPhrases(raw, sd.Track.Source)
+	if opts.Lang == "" {
+		opts.Lang = sd.Track.Lang
+	}
+	phrases, err := subtitles.TransformRawToPhrases(raw, sd.Track.Source, opts)
 	if err != nil {
 		return empty, fmt.Errorf("transform subs: %w", err)
 	}
 
 	tr := subtitles.NewTranscript(sd.Title, sd.Track, phrases, m.Chapters)
+	tr.Reason = sd.Reason
+	tr.URL = m.WatchURL()
 	return tr, err
 }
 
+// BuildBilingual télécharge la piste en langue `lang` (voir subtitles.SecondTrack),
+// aligne ses phrases sur celles de tr et retourne le document Markdown bilingue.
+// Les segments SponsorBlock de m sont traités sur la seconde piste comme sur tr (segMode).
+// ErrNoSubtitle si aucune seconde piste n'est utilisable. maxBytes : voir FetchSubtitleDownload.
+func BuildBilingual(ctx context.Context, m *model.Meta, tr subtitles.Transcript, lang string,
+	layout subtitles.BilingualLayout, segMode subtitles.SegmentMode, opts subtitles.Options, maxBytes int64) ([]byte, error) {
+	primary := subtitles.TrackSelection{Track: tr.Track, Reason: tr.Reason}
+	sel, ok := subtitles.SecondTrack(m, primary, lang)
+	if !ok {
+		return nil, subtitles.ErrNoSubtitle
+	}
+
+	sd := subtitles.SubtitleDownload{Title: tr.Title, Track: sel.Track, Reason: sel.Reason}
+	sd, err := subtitles.DownloadTrack(ctx, sd, subtitleTimeout, maxBytes, false)
+	if err != nil {
+		return nil, fmt.Errorf("bilingue: %w", err)
+	}
+	second, err := BuildTranscriptFromSubtitleDownload(&sd, m, opts)
+	if err != nil {
+		return nil, fmt.Errorf("bilingue: %w", err)
+	}
+	if len(m.Segments) > 0 {
+		second.Phrases, _ = subtitles.ApplySegments(second.Phrases, m.Segments, segMode)
+	}
+
+	rows := subtitles.AlignPhrases(tr.Phrases, second.Phrases, subtitles.DefaultAlignToleranceMs)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", tr.Title)
+	for _, t := range []subtitles.Transcript{tr, second} {
+		if notice := t.TranslationNotice(); notice != "" {
+			fmt.Fprintf(&b, "%s\n\n", notice)
+		}
+	}
+	b.WriteString(subtitles.RenderBilingual(rows, layout, tr.Track.Lang, second.Track.Lang, m.WatchURL()))
+	return []byte(b.String()), nil
+}
+
+// BilingualFilename : "<titre> (ja-en).md"
+func BilingualFilename(title, leftLang, rightLang string) string {
+	return fmt.Sprintf("%s (%s-%s).md", fsutil.SanitizeFilename(title), leftLang, rightLang)
+}
+
+// CompareWithCounterpart télécharge la piste de l'autre source (voir
+// subtitles.CounterpartTrack) et compare captions auto et sous-titres manuels.
+// sd est la piste déjà téléchargée ; clean (nil = aucun) est appliqué aux deux pistes
+// pour que balises sonores et mots de remplissage ne comptent pas comme erreurs.
+// ErrNoSubtitle si la vidéo n'a pas les deux sources.
+func CompareWithCounterpart(ctx context.Context, m *model.Meta, sd subtitles.SubtitleDownload,
+	opts subtitles.Options, clean *subtitles.CleanupOptions, maxBytes int64) (subtitles.ASRComparison, error) {
+	primary := subtitles.TrackSelection{Track: sd.Track, Reason: sd.Reason}
+	sel, ok := subtitles.CounterpartTrack(m, primary)
+	if !ok {
+		return subtitles.ASRComparison{}, subtitles.ErrNoSubtitle
+	}
+	other := subtitles.SubtitleDownload{Title: sd.Title, Track: sel.Track, Reason: sel.Reason}
+	other, err := subtitles.DownloadTrack(ctx, other, subtitleTimeout, maxBytes, false)
+	if err != nil {
+		return subtitles.ASRComparison{}, fmt.Errorf("comparaison asr: %w", err)
+	}
+	manual, err := BuildTranscriptFromSubtitleDownload(&sd, m, opts)
+	if err != nil {
+		return subtitles.ASRComparison{}, fmt.Errorf("comparaison asr: %w", err)
+	}
+	auto, err := BuildTranscriptFromSubtitleDownload(&other, m, opts)
+	if err != nil {
+		return subtitles.ASRComparison{}, fmt.Errorf("comparaison asr: %w", err)
+	}
+	if clean != nil {
+		manual.Phrases, _ = subtitles.CleanPhrases(manual.Phrases, *clean)
+		auto.Phrases, _ = subtitles.CleanPhrases(auto.Phrases, *clean)
+	}
+	if sd.Track.Source == model.SubSourceAutomatic {
+		manual, auto = auto, manual
+	}
+	return subtitles.CompareASR(manual.Phrases, auto.Phrases, manual.Track.Lang), nil
+}
+
+// ChapterTexts retourne le transcript de chaque chapitre (chapitres triés),
+// rendu comme le txt (paragraphes, dialogue) mais sans titres de chapitre.
+func ChapterTexts(tr subtitles.Transcript) []string {
+	texts := make([]string, len(tr.Chapters))
+	for i := range texts {
+		sub := tr
+		sub.Phrases = tr.ChapterSlice(i)
+		sub.Chapters = nil
+		if len(sub.Phrases) > 0 {
+			texts[i] = strings.TrimSpace(sub.Text())
+		}
+	}
+	return texts
+}
+
+// SplitNotes indique si la vidéo mérite une note par chapitre : assez de
+// chapitres et, si la durée est connue, assez longue.
+func SplitNotes(chapters []model.Chapter, duration model.Seconds, minChapters, minDurationMin int) bool {
+	if len(chapters) < minChapters {
+		return false
+	}
+	return duration == 0 || int64(duration) >= int64(minDurationMin)*60
+}
+
+// ASRReportFilename : nom du rapport de comparaison, ex: "Titre (asr en).md".
+func ASRReportFilename(title, lang string) string {
+	return fmt.Sprintf("%s (asr %s).md", fsutil.SanitizeFilename(title), lang)
+}
+
 // SaveTranscript sauvegarde le transcript avec fsutil.WriteFileAtomic
 func SaveTranscript(tr subtitles.Transcript, format model.Format, outDir string) error {
 	if len(tr.Phrases) == 0 {
@@ -103,37 +222,78 @@ func SaveTranscript(tr subtitles.Transcript, format model.Format, outDir string)
 		return fmt.Errorf("SaveTranscript: %w", err)
 	}
 	path := filepath.Join(outDir, filename)
-	data := []byte(tr.Plain())
+	data, err := tr.Render(format)
+	if err != nil {
+		return fmt.Errorf("SaveTranscript: %w", err)
+	}
 	if werr := fsutil.WriteFileAtomic(path, data, 0o644); werr != nil {
 		return fmt.Errorf("write subtitle %s: %w", path, werr)
 	}
 	return nil
 }
 
-// BuildFullChatPrompt construit le prompt complet : prompt + texte.
+// PromptOptions regroupe ce qui est ajouté au prompt en plus du transcript.
+type PromptOptions struct {
+	SplitThreshold int             // taille au-delà de laquelle ErrPromptTooLong est signalée
+	Comments       []model.Comment // commentaires ajoutés en contexte (déjà filtrés/triés)
+	KnownTerms     []string        // orthographe de référence des termes du glossaire
+}
+
+// BuildFullChatPrompt construit le prompt complet : prompt + texte (+ contexte optionnel).
 // retourne [][]byte car on prévoit d'ajouter le prompt splitting
-func BuildFullChatPrompt(t subtitles.Transcript, promptSplitThreshold int) ([][]byte, error) {
+func BuildFullChatPrompt(t subtitles.Transcript, opts PromptOptions) ([][]byte, error) {
 	var out [][]byte
 	p, err := ia.GetChatPrompt()
 	if err != nil {
 		return nil, fmt.Errorf("erreur de construction du prompt: %w", err)
 	}
 	tc := t.Collapsed()
-	fullLen := len(p) + len(tc)
+	if t.HasSpeakers() {
+		// dialogue : une réplique par paragraphe, les tours de parole sont conservés
+		tc = t.Dialogue()
+	}
 
 	var ptc bytes.Buffer
 	ptc.Write(p)
 	ptc.WriteString("\n\n")
+	if notice := t.TranslationNotice(); notice != "" {
+		ptc.WriteString(notice)
+		ptc.WriteString("\n")
+	}
+	if speakers := t.Speakers(); len(speakers) > 0 {
+		ptc.WriteString("[Dialogue entre " + strings.Join(speakers, ", ") +
+			" : une réplique par paragraphe, préfixée du locuteur. Attribue idées et citations à leur auteur.]\n")
+	}
+	if len(opts.KnownTerms) > 0 {
+		ptc.WriteString("[Termes connus, à orthographier ainsi : " + strings.Join(opts.KnownTerms, ", ") + "]\n")
+	}
 	ptc.WriteString(tc)
 
+	if len(opts.Comments) > 0 {
+		ptc.WriteString("\n\n")
+		ptc.WriteString(commentsPromptSection(opts.Comments))
+	}
+
 	out = append(out, ptc.Bytes())
 
-	if fullLen > promptSplitThreshold {
-		return out, fmt.Errorf("%w: taille totale %d > %d", ErrPromptTooLong, fullLen, promptSplitThreshold)
+	fullLen := ptc.Len()
+	if fullLen > opts.SplitThreshold {
+		return out, fmt.Errorf("%w: taille totale %d > %d", ErrPromptTooLong, fullLen, opts.SplitThreshold)
 	}
 	return out, nil
 }
 
+// commentsPromptSection formate les commentaires en contexte pour l'IA.
+func commentsPromptSection(comments []model.Comment) string {
+	var b strings.Builder
+	b.WriteString("Commentaires les plus appréciés (contexte, peuvent contenir des corrections) :\n")
+	for _, c := range comments {
+		text := strings.Join(strings.Fields(c.Text), " ")
+		b.WriteString(fmt.Sprintf("- %s (%d j'aime) : %s\n", c.Author, c.Likes, text))
+	}
+	return b.String()
+}
+
 func (a *App) WaitForAIResponse(ctx context.Context, initialPrompt string) (string, bool, error) {
 	// initialPrompt : le contenu copié initialement dans le clipboard (le prompt)
 	if a.cfg.AutoMode {
@@ -223,3 +383,107 @@ func (a App) YtDlpUpdateCheck(ctx context.Context, timeout time.Duration, versio
 
 	return nil
 }
+
+// TracksReport liste les pistes de sous-titres disponibles (manuelles, automatiques,
+// traductions) et signale celle qui serait retenue (`selected`, peut être nil).
+// Les traductions étant nombreuses, seules leurs langues sont listées.
+func TracksReport(m *model.Meta, selected *subtitles.TrackSelection) string {
+	var b strings.Builder
+	orig := m.OriginalLang()
+	if orig == "" {
+		orig = "inconnue"
+	}
+	fmt.Fprintf(&b, "Langue originale : %s\n", orig)
+
+	section := func(title string, tracks []model.SubtitleTrack) {
+		fmt.Fprintf(&b, "%s (%d) :\n", title, len(tracks))
+		if len(tracks) == 0 {
+			b.WriteString("    (aucune)\n")
+			return
+		}
+		for _, t := range tracks {
+			mark := "   "
+			suffix := ""
+			if selected != nil && t == selected.Track {
+				mark = " * "
+				suffix = "  <- " + selected.String()
+			}
+			fmt.Fprintf(&b, "%s %s [%s]%s\n", mark, t.Lang, t.Format, suffix)
+		}
+	}
+	section("Sous-titres manuels", m.ManualSubs)
+	section("Sous-titres automatiques", m.AutoSubs)
+
+	fmt.Fprintf(&b, "Traductions automatiques (%d) :\n", len(m.TranslatedSubs))
+	if len(m.TranslatedSubs) == 0 {
+		b.WriteString("    (aucune)\n")
+	} else {
+		langs := make([]string, 0, len(m.TranslatedSubs))
+		for _, t := range m.TranslatedSubs {
+			langs = append(langs, t.Lang)
+		}
+		fmt.Fprintf(&b, "    %s\n", strings.Join(langs, ", "))
+	}
+	if selected != nil {
+		fmt.Fprintf(&b, "Piste retenue : %s, %s\n", selected, selected.Track.Source)
+	}
+	return b.String()
+}
+
+// StatsReport détaille les statistiques d'une vidéo (`subscribe stats <titre>`).
+func StatsReport(s model.TranscriptStats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q (%s, %s)\n", s.Title, s.VideoID, s.Lang)
+	fmt.Fprintf(&b, "Mots : %d, lecture %s\n", s.Words, s.ReadingTime())
+	fmt.Fprintf(&b, "Débit : %s mots/min sur %s de parole (couverture %s)\n",
+		formatWPM(s.WPM), model.Seconds(s.SpeechMs/1000).Human(), s.CoveragePercent())
+	if len(s.Chapters) > 0 {
+		b.WriteString("Chapitres :\n")
+		for _, c := range s.Chapters {
+			fmt.Fprintf(&b, "    %s %s : %d mots, %s mots/min\n",
+				model.Seconds(c.StartMs/1000).TimestampHHMMSS(), c.Title, c.Words, formatWPM(c.WPM))
+		}
+	}
+	if len(s.Gaps) > 0 {
+		fmt.Fprintf(&b, "Trous (%d, %s) :\n", len(s.Gaps), model.Seconds(s.GapsMs()/1000).Human())
+		for _, g := range s.Gaps {
+			fmt.Fprintf(&b, "    %s - %s (%s)\n", model.Seconds(g.StartMs/1000).TimestampHHMMSS(),
+				model.Seconds(g.EndMs/1000).TimestampHHMMSS(), model.Seconds(g.DurationMs()/1000).Human())
+		}
+	}
+	if len(s.TopTerms) > 0 {
+		terms := make([]string, len(s.TopTerms))
+		for i, t := range s.TopTerms {
+			terms[i] = fmt.Sprintf("%s ×%d", t.Term, t.Count)
+		}
+		fmt.Fprintf(&b, "Termes fréquents : %s\n", strings.Join(terms, ", "))
+	}
+	if len(s.Keywords) > 0 {
+		fmt.Fprintf(&b, "Mots-clés : %s\n", strings.Join(s.Keywords, ", "))
+	}
+	return b.String()
+}
+
+// LibraryReport résume la bibliothèque (`subscribe stats`) : une ligne par vidéo,
+// avec ses mots-clés face aux autres.
+func LibraryReport(corpus []model.TranscriptStats, keywords int) string {
+	var b strings.Builder
+	words := 0
+	for _, d := range corpus {
+		words += d.Words
+	}
+	fmt.Fprintf(&b, "Bibliothèque : %d vidéos, %d mots\n", len(corpus), words)
+	for _, d := range corpus {
+		fmt.Fprintf(&b, "- %s : %d mots, %s mots/min", d.Title, d.Words, formatWPM(d.WPM))
+		if kw := library.Keywords(d, corpus, keywords); len(kw) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(kw, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatWPM : débit arrondi à l'unité, ex: "152".
+func formatWPM(wpm float64) string {
+	return fmt.Sprintf("%.0f", wpm)
+}
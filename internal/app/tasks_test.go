@@ -0,0 +1,47 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/internal/subtitles"
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestSplitNotes(t *testing.T) {
+	three := []model.Chapter{{Start: 0}, {Start: 600}, {Start: 1200}}
+	tests := []struct {
+		name     string
+		chapters []model.Chapter
+		duration model.Seconds
+		want     bool
+	}{
+		{"assez de chapitres et assez long", three, 3600, true},
+		{"trop court", three, 3599, false},
+		{"pas assez de chapitres", three[:2], 7200, false},
+		{"durée inconnue", three, 0, true},
+		{"aucun chapitre", nil, 7200, false},
+	}
+	for _, tt := range tests {
+		if got := SplitNotes(tt.chapters, tt.duration, 3, 60); got != tt.want {
+			t.Errorf("%s : SplitNotes = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChapterTexts(t *testing.T) {
+	tr := subtitles.Transcript{
+		Phrases: []subtitles.Phrase{
+			{TimestampMs: 0, Text: "Bonjour."},
+			{TimestampMs: 70_000, Text: "Premier point."},
+			{TimestampMs: 200_000, Text: "Merci."},
+		},
+		Chapters:    []model.Chapter{{Start: 0, Title: "Intro"}, {Start: 60, Title: "Corps"}, {Start: 100, Title: "Fin"}},
+		ChapterSnap: subtitles.ChapterSnap{MaxMs: 15_000}, // "Corps" recalé sur 70 s, "Fin" hors de portée
+	}
+	got := ChapterTexts(tr)
+	want := []string{"Bonjour.", "Premier point.", "Merci."}
+	if !slices.Equal(got, want) {
+		t.Errorf("ChapterTexts = %q; want %q", got, want)
+	}
+}
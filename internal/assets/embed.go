@@ -13,11 +13,15 @@ const DefaultConfigAsset = "subscribe.example.yaml"
 // Ce sont des chemins relatifs DANS Embedded (ex: "templates/obsidian_note.md.tmpl").
 var DefaultTemplatePaths = []string{
 	"templates/obsidian_note.md.tmpl",
+	"templates/obsidian_parent.md.tmpl",
+	"templates/obsidian_chapter.md.tmpl",
 	"templates/prompt_for_ai.txt.tmpl",
 }
 
 // TemplateByName donne un accès par clé (map).
 var TemplateByName = map[string]string{
-	"obsidian_note": "templates/obsidian_note.md.tmpl",
-	"ai_prompt":     "templates/prompt_for_ai.txt.tmpl",
+	"obsidian_note":    "templates/obsidian_note.md.tmpl",
+	"obsidian_parent":  "templates/obsidian_parent.md.tmpl",
+	"obsidian_chapter": "templates/obsidian_chapter.md.tmpl",
+	"ai_prompt":        "templates/prompt_for_ai.txt.tmpl",
 }
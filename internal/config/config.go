@@ -2,19 +2,61 @@ package config
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/patrickprogramme/subscribe/internal/assets"
 	"github.com/patrickprogramme/subscribe/internal/fsutil"
+	"github.com/patrickprogramme/subscribe/internal/sponsorblock"
+	"github.com/patrickprogramme/subscribe/internal/subtitles"
 	"gopkg.in/yaml.v3"
 )
 
 const CurrentConfigVersion = 1
 
+// StringList accepte en YAML une valeur seule ou une liste :
+// `transcript_format: "md"` et `transcript_format: ["md", "srt"]` sont équivalents.
+type StringList []string
+
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*l = StringList{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var xs []string
+		if err := node.Decode(&xs); err != nil {
+			return err
+		}
+		*l = xs
+		return nil
+	default:
+		return fmt.Errorf("ligne %d : valeur ou liste attendue", node.Line)
+	}
+}
+
+// ParseSpeakerNames lit la valeur du flag -speakers : "S1=Alice,S2=Bob".
+func ParseSpeakerNames(s string) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		label, name, ok := strings.Cut(pair, "=")
+		label, name = strings.TrimSpace(label), strings.TrimSpace(name)
+		if !ok || label == "" || name == "" {
+			return nil, fmt.Errorf("locuteur %q : format attendu ETIQUETTE=Nom", pair)
+		}
+		names[label] = name
+	}
+	return names, nil
+}
+
 // struct pour les paramètres de configuration
 type Config struct {
 	// Chemins
@@ -30,10 +72,126 @@ type Config struct {
 	// Sous-titres
 	PreferManualSubs bool `yaml:"prefer_manual_subs"`
 	SaveRawSubs      bool `yaml:"save_raw_subs"`
+	// MaxSubtitleMB : taille maximale d'une piste téléchargée (les lives de plusieurs heures dépassent 10 Mo)
+	MaxSubtitleMB int `yaml:"max_subtitle_mb"`
+	// PreferredLanguages : après la langue originale, langues essayées dans l'ordre
+	PreferredLanguages []string `yaml:"preferred_languages"`
+	// TranslateTo : langue cible ; si la piste choisie est dans une autre langue,
+	// la traduction automatique de YouTube (tlang) est utilisée. "" = désactivé
+	TranslateTo string `yaml:"translate_to"`
+
+	// Transcript bilingue : piste principale + seconde piste alignées par timestamps
+	Bilingual struct {
+		Enabled bool   `yaml:"enabled"`
+		Lang    string `yaml:"lang"`   // langue de la seconde piste (traduction auto si absente)
+		Layout  string `yaml:"layout"` // "table" ou "interleaved"
+	} `yaml:"bilingual"`
+
+	// Comparaison captions auto / sous-titres manuels (WER), rapport "<titre> (asr en).md"
+	ASRCheck struct {
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"asr_check"`
+
+	// Commentaires (yt-dlp --write-comments)
+	Comments struct {
+		Enabled  bool   `yaml:"enabled"`
+		Limit    int    `yaml:"limit"`     // nombre max de commentaires récupérés
+		Sort     string `yaml:"sort"`      // "top" ou "new"
+		MaxDepth int    `yaml:"max_depth"` // 1 = sans les réponses
+		InPrompt int    `yaml:"in_prompt"` // N meilleurs commentaires ajoutés au prompt IA (0 = aucun)
+	} `yaml:"comments"`
+
+	// SponsorBlock : segments sponsorisés retirés ou marqués dans le transcript
+	SponsorBlock struct {
+		Enabled    bool     `yaml:"enabled"`
+		APIURL     string   `yaml:"api_url"`
+		Categories []string `yaml:"categories"`
+		Mode       string   `yaml:"mode"` // "strip" ou "tag"
+	} `yaml:"sponsorblock"`
+
+	// Heatmap "les plus revus" -> section Moments clés
+	Heatmap struct {
+		KeyMoments int `yaml:"key_moments"` // nombre de pics retenus (0 = désactivé)
+		MinGapSec  int `yaml:"min_gap_sec"` // écart minimal entre deux pics
+	} `yaml:"heatmap"`
 
 	// Transcription
-	SaveTranscript   bool   `yaml:"save_transcript"`
-	TranscriptFormat string `yaml:"transcript_format"`
+	SaveTranscript bool `yaml:"save_transcript"`
+	// TranscriptFormats : "txt" ou liste ["md", "srt"] (txt, md, srt, vtt, json)
+	TranscriptFormats StringList `yaml:"transcript_format"`
+	// WordTimings : garder les timings par mot (export json)
+	WordTimings bool `yaml:"word_timings"`
+	// Sentences : découpage du texte des sous-titres en phrases
+	Sentences struct {
+		BreakOnAbbreviations bool     `yaml:"break_on_abbreviations"` // couper après "Dr.", "e.g."...
+		Abbreviations        []string `yaml:"abbreviations"`          // ajoutées aux listes intégrées (en, fr, de, es)
+		MergeShortPhrases    bool     `yaml:"merge_short_phrases"`
+		MinPhraseWords       int      `yaml:"min_phrase_words"` // en dessous : fusion avec une voisine
+	} `yaml:"sentences"`
+	// RestorePunctuation : ponctuation et casse des captions auto non ponctuées
+	RestorePunctuation struct {
+		Mode        string  `yaml:"mode"`         // "off", "auto" (captions non ponctuées) ou "always"
+		PauseFactor float64 `yaml:"pause_factor"` // pause / intervalle médian entre mots marquant une fin de phrase
+		MinWords    int     `yaml:"min_words"`
+		MaxWords    int     `yaml:"max_words"`
+	} `yaml:"restore_punctuation"`
+	// Cleanup : bruit ASR retiré après la construction des phrases
+	Cleanup struct {
+		Enabled      bool     `yaml:"enabled"`
+		Strict       bool     `yaml:"strict"`     // ne jamais modifier les mots (balises sonores uniquement)
+		SoundTags    bool     `yaml:"sound_tags"` // [Music], [Applause], [Rires]
+		Censored     bool     `yaml:"censored"`   // [ __ ]
+		Stutters     bool     `yaml:"stutters"`   // "the the"
+		Fillers      bool     `yaml:"fillers"`    // "um", "euh", "du coup"
+		ExtraFillers []string `yaml:"extra_fillers"`
+	} `yaml:"cleanup"`
+	// Glossary : corrections de l'ASR (termes métier, noms propres), voir internal/glossary
+	Glossary struct {
+		Enabled  bool     `yaml:"enabled"`
+		Files    []string `yaml:"files"`     // chemins ou motifs, relatifs au fichier de config
+		InPrompt bool     `yaml:"in_prompt"` // liste des termes connus ajoutée au prompt IA
+	} `yaml:"glossary"`
+	// Speakers : changements de locuteur des sous-titres (tiret, >>, "NOM:")
+	Speakers struct {
+		Enabled bool              `yaml:"enabled"`
+		Names   map[string]string `yaml:"names"` // étiquette -> nom ("S1": "Alice", "JOHN": "John Doe")
+	} `yaml:"speakers"`
+	// ChapterSnap : recalage des chapitres sur le début des phrases dans les transcripts
+	ChapterSnap struct {
+		Mode   string `yaml:"mode"`    // "nearest", "next_sentence" ou "none"
+		MaxSec int    `yaml:"max_sec"` // distance maximale du recalage (0 = sans limite)
+	} `yaml:"chapter_snap"`
+	// AutoChapters : chapitres générés depuis le transcript pour les vidéos qui n'en ont pas
+	AutoChapters struct {
+		Enabled       bool  `yaml:"enabled"`
+		MinChapterSec int   `yaml:"min_chapter_sec"` // durée minimale d'un chapitre
+		PauseMs       int64 `yaml:"pause_ms"`        // silence qui renforce un changement de sujet (0 = ignoré)
+		TitleWords    int   `yaml:"title_words"`     // termes repris dans le titre
+	} `yaml:"auto_chapters"`
+	// SplitNotes : une note par chapitre et une note parente pour les longues vidéos
+	SplitNotes struct {
+		Enabled         bool   `yaml:"enabled"`
+		MinChapters     int    `yaml:"min_chapters"`     // en dessous, une seule note
+		MinDurationMin  int    `yaml:"min_duration_min"` // vidéos plus courtes : une seule note (0 = toujours découper)
+		ParentTemplate  string `yaml:"parent_template"`  // fichier *.md.tmpl du dossier templates
+		ChapterTemplate string `yaml:"chapter_template"`
+	} `yaml:"split_notes"`
+	// Stats : statistiques du transcript ("<titre> (stats).json") et mots-clés TF-IDF
+	Stats struct {
+		Enabled   bool     `yaml:"enabled"`
+		GapSec    int      `yaml:"gap_sec"`   // trous signalés au-delà de cette durée
+		TopTerms  int      `yaml:"top_terms"` // termes les plus fréquents retenus
+		Keywords  int      `yaml:"keywords"`  // mots-clés TF-IDF retenus (0 = aucun)
+		StopWords []string `yaml:"stop_words"`
+	} `yaml:"stats"`
+	// Paragraphs : découpage en paragraphes des transcripts txt et md
+	Paragraphs struct {
+		Enabled     bool     `yaml:"enabled"`
+		PauseMs     int64    `yaml:"pause_ms"`     // silence qui force un nouveau paragraphe
+		TargetWords int      `yaml:"target_words"` // longueur visée en mots
+		MaxWords    int      `yaml:"max_words"`    // longueur maximale en mots
+		Markers     []string `yaml:"markers"`      // marqueurs de discours ("so", "alors"...)
+	} `yaml:"paragraphs"`
 
 	// Mode automatique
 	AutoMode bool `yaml:"auto_mode"`
@@ -47,8 +205,16 @@ type Config struct {
 		Name            string `yaml:"name"`
 		Path            string `yaml:"path"`
 		ShowWarnings    bool   `yaml:"show_warnings"`
+		Verbose         bool   `yaml:"verbose"`
 		AutoUpdateCheck bool   `yaml:"auto_update_check"`
 
+		// Retry : uniquement pour les erreurs transitoires (HTTP 429, réseau)
+		Retry struct {
+			MaxAttempts     int `yaml:"max_attempts"`
+			InitialDelaySec int `yaml:"initial_delay_sec"`
+			MaxDelaySec     int `yaml:"max_delay_sec"`
+		} `yaml:"retry"`
+
 		// ResolvedPath contient le chemin effectif vers l'exécutable
 		ResolvedPath string `yaml:"-"`
 	} `yaml:"yt_dlp"`
@@ -76,10 +242,80 @@ func defaultConfig() *Config {
 	// Sous-titres
 	c.PreferManualSubs = true
 	c.SaveRawSubs = false
+	c.MaxSubtitleMB = 50
+	c.PreferredLanguages = []string{"fr", "en"}
+	c.TranslateTo = ""
+
+	// Transcript bilingue
+	c.Bilingual.Enabled = false
+	c.Bilingual.Lang = "en"
+	c.Bilingual.Layout = "table"
+
+	// Comparaison ASR
+	c.ASRCheck.Enabled = false
+
+	// Commentaires
+	c.Comments.Enabled = false
+	c.Comments.Limit = 100
+	c.Comments.Sort = "top"
+	c.Comments.MaxDepth = 1
+	c.Comments.InPrompt = 0
+
+	// SponsorBlock
+	c.SponsorBlock.Enabled = false
+	c.SponsorBlock.APIURL = sponsorblock.DefaultAPIURL
+	c.SponsorBlock.Categories = slices.Clone(sponsorblock.DefaultCategories)
+	c.SponsorBlock.Mode = "strip"
+
+	// Heatmap
+	c.Heatmap.KeyMoments = 5
+	c.Heatmap.MinGapSec = 60
 
 	// Transcription
 	c.SaveTranscript = true
-	c.TranscriptFormat = "txt"
+	c.TranscriptFormats = StringList{"txt"}
+	c.WordTimings = false
+	c.Sentences.BreakOnAbbreviations = false
+	c.Sentences.Abbreviations = nil
+	c.Sentences.MergeShortPhrases = false
+	c.Sentences.MinPhraseWords = 3
+	c.RestorePunctuation.Mode = "off"
+	c.RestorePunctuation.PauseFactor = 2.5
+	c.RestorePunctuation.MinWords = 4
+	c.RestorePunctuation.MaxWords = 30
+	c.Cleanup.Enabled = false
+	c.Cleanup.Strict = false
+	c.Cleanup.SoundTags = true
+	c.Cleanup.Censored = true
+	c.Cleanup.Stutters = true
+	c.Cleanup.Fillers = true
+	c.Cleanup.ExtraFillers = nil
+	c.Glossary.Enabled = false
+	c.Glossary.Files = []string{"glossary.yaml"}
+	c.Glossary.InPrompt = true
+	c.Speakers.Enabled = false
+	c.Speakers.Names = nil
+	c.ChapterSnap.Mode = "nearest"
+	c.ChapterSnap.MaxSec = 15
+	c.AutoChapters.Enabled = false
+	c.AutoChapters.MinChapterSec = 120
+	c.AutoChapters.PauseMs = 3000
+	c.AutoChapters.TitleWords = 3
+	c.SplitNotes.Enabled = false
+	c.SplitNotes.MinChapters = 3
+	c.SplitNotes.MinDurationMin = 60
+	c.SplitNotes.ParentTemplate = "obsidian_parent.md.tmpl"
+	c.SplitNotes.ChapterTemplate = "obsidian_chapter.md.tmpl"
+	c.Stats.Enabled = false
+	c.Stats.GapSec = 10
+	c.Stats.TopTerms = 10
+	c.Stats.Keywords = 8
+	c.Stats.StopWords = nil
+	c.Paragraphs.Enabled = false
+	c.Paragraphs.PauseMs = 2000
+	c.Paragraphs.TargetWords = 120
+	c.Paragraphs.MaxWords = 220
+	c.Paragraphs.Markers = subtitles.DefaultDiscourseMarkers()
 
 	// Mode automatique
 	c.AutoMode = false
@@ -92,7 +328,11 @@ func defaultConfig() *Config {
 	c.YtDlp.Name = "yt-dlp"
 	c.YtDlp.Path = ""
 	c.YtDlp.ShowWarnings = false
+	c.YtDlp.Verbose = false
 	c.YtDlp.AutoUpdateCheck = false
+	c.YtDlp.Retry.MaxAttempts = 3
+	c.YtDlp.Retry.InitialDelaySec = 2
+	c.YtDlp.Retry.MaxDelaySec = 30
 
 	c.ConfigVersion = CurrentConfigVersion
 
@@ -172,19 +412,179 @@ func (c *Config) normalizeConfig() {
 	c.ObsidianVaultDir = filepath.Clean(c.ObsidianVaultDir)
 
 	// Trim and normalize strings
-	c.TranscriptFormat = strings.TrimSpace(strings.ToLower(c.TranscriptFormat))
-	if c.TranscriptFormat == "" {
-		c.TranscriptFormat = "txt"
+	formats := StringList{}
+	for _, f := range c.TranscriptFormats {
+		f = strings.TrimSpace(strings.ToLower(f))
+		if f != "" && !slices.Contains(formats, f) {
+			formats = append(formats, f)
+		}
 	}
+	if len(formats) == 0 {
+		formats = StringList{"txt"}
+	}
+	c.TranscriptFormats = formats
 
 	if c.PromptSplitThreshold <= 0 {
 		c.PromptSplitThreshold = 32000
 	}
+	if c.MaxSubtitleMB <= 0 {
+		c.MaxSubtitleMB = 50
+	}
+
+	// langues préférées : sans espaces ni entrées vides
+	langs := c.PreferredLanguages[:0]
+	for _, l := range c.PreferredLanguages {
+		if l = strings.TrimSpace(l); l != "" {
+			langs = append(langs, l)
+		}
+	}
+	c.PreferredLanguages = langs
+	c.TranslateTo = strings.TrimSpace(c.TranslateTo)
+
+	// bilingue
+	c.Bilingual.Lang = strings.TrimSpace(c.Bilingual.Lang)
+	if c.Bilingual.Lang == "" {
+		c.Bilingual.Lang = "en"
+	}
+	c.Bilingual.Layout = strings.TrimSpace(strings.ToLower(c.Bilingual.Layout))
+	if c.Bilingual.Layout != "interleaved" {
+		c.Bilingual.Layout = "table"
+	}
+
+	// phrases
+	if c.Sentences.MinPhraseWords < 1 {
+		c.Sentences.MinPhraseWords = 1
+	}
+
+	// restauration de la ponctuation
+	c.RestorePunctuation.Mode = strings.TrimSpace(strings.ToLower(c.RestorePunctuation.Mode))
+	if c.RestorePunctuation.Mode != "auto" && c.RestorePunctuation.Mode != "always" {
+		c.RestorePunctuation.Mode = "off"
+	}
+	if c.RestorePunctuation.PauseFactor <= 0 {
+		c.RestorePunctuation.PauseFactor = 2.5
+	}
+	if c.RestorePunctuation.MinWords < 1 {
+		c.RestorePunctuation.MinWords = 1
+	}
+	if c.RestorePunctuation.MaxWords < c.RestorePunctuation.MinWords {
+		c.RestorePunctuation.MaxWords = c.RestorePunctuation.MinWords
+	}
+
+	// locuteurs : noms sans espaces autour, entrées vides retirées
+	names := make(map[string]string, len(c.Speakers.Names))
+	for k, v := range c.Speakers.Names {
+		if k, v = strings.TrimSpace(k), strings.TrimSpace(v); k != "" && v != "" {
+			names[k] = v
+		}
+	}
+	c.Speakers.Names = names
+
+	// glossaires : chemins vides retirés
+	files := c.Glossary.Files[:0]
+	for _, f := range c.Glossary.Files {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	c.Glossary.Files = files
+
+	// recalage des chapitres : mode inconnu -> nearest
+	c.ChapterSnap.Mode = strings.TrimSpace(strings.ToLower(c.ChapterSnap.Mode))
+	if c.ChapterSnap.Mode != "next_sentence" && c.ChapterSnap.Mode != "none" {
+		c.ChapterSnap.Mode = "nearest"
+	}
+	c.ChapterSnap.MaxSec = max(c.ChapterSnap.MaxSec, 0)
+
+	// chapitres générés : au moins 30 s par chapitre, 1 à 6 termes par titre
+	c.AutoChapters.MinChapterSec = max(c.AutoChapters.MinChapterSec, 30)
+	c.AutoChapters.PauseMs = max(c.AutoChapters.PauseMs, 0)
+	c.AutoChapters.TitleWords = min(max(c.AutoChapters.TitleWords, 1), 6)
+
+	// notes par chapitre : au moins 2 chapitres, templates désignés par leur nom de fichier
+	c.SplitNotes.MinChapters = max(c.SplitNotes.MinChapters, 2)
+	c.SplitNotes.MinDurationMin = max(c.SplitNotes.MinDurationMin, 0)
+	c.SplitNotes.ParentTemplate = filepath.Base(cmp.Or(strings.TrimSpace(c.SplitNotes.ParentTemplate), "obsidian_parent.md.tmpl"))
+	c.SplitNotes.ChapterTemplate = filepath.Base(cmp.Or(strings.TrimSpace(c.SplitNotes.ChapterTemplate), "obsidian_chapter.md.tmpl"))
+
+	// statistiques : valeurs jamais négatives
+	c.Stats.GapSec = max(c.Stats.GapSec, 0)
+	c.Stats.TopTerms = max(c.Stats.TopTerms, 0)
+	c.Stats.Keywords = max(c.Stats.Keywords, 0)
+
+	// paragraphes : max_words jamais sous target_words, marqueurs en minuscules
+	if c.Paragraphs.PauseMs < 0 {
+		c.Paragraphs.PauseMs = 0
+	}
+	if c.Paragraphs.TargetWords < 0 {
+		c.Paragraphs.TargetWords = 0
+	}
+	if c.Paragraphs.MaxWords < c.Paragraphs.TargetWords {
+		c.Paragraphs.MaxWords = c.Paragraphs.TargetWords
+	}
+	markers := c.Paragraphs.Markers[:0]
+	for _, m := range c.Paragraphs.Markers {
+		if m = strings.TrimSpace(strings.ToLower(m)); m != "" {
+			markers = append(markers, m)
+		}
+	}
+	c.Paragraphs.Markers = markers
+
+	// commentaires
+	c.Comments.Sort = strings.TrimSpace(strings.ToLower(c.Comments.Sort))
+	if c.Comments.Sort != "new" {
+		c.Comments.Sort = "top"
+	}
+	if c.Comments.InPrompt < 0 {
+		c.Comments.InPrompt = 0
+	}
+
+	// sponsorblock
+	c.SponsorBlock.APIURL = strings.TrimRight(strings.TrimSpace(c.SponsorBlock.APIURL), "/")
+	if c.SponsorBlock.APIURL == "" {
+		c.SponsorBlock.APIURL = sponsorblock.DefaultAPIURL
+	}
+	c.SponsorBlock.Mode = strings.TrimSpace(strings.ToLower(c.SponsorBlock.Mode))
+	if c.SponsorBlock.Mode != "tag" {
+		c.SponsorBlock.Mode = "strip"
+	}
+
+	// heatmap
+	if c.Heatmap.KeyMoments < 0 {
+		c.Heatmap.KeyMoments = 0
+	}
+	if c.Heatmap.MinGapSec < 0 {
+		c.Heatmap.MinGapSec = 0
+	}
+
+	// retry : au moins une tentative, délais jamais négatifs
+	if c.YtDlp.Retry.MaxAttempts < 1 {
+		c.YtDlp.Retry.MaxAttempts = 1
+	}
+	if c.YtDlp.Retry.InitialDelaySec < 0 {
+		c.YtDlp.Retry.InitialDelaySec = 0
+	}
+	if c.YtDlp.Retry.MaxDelaySec < c.YtDlp.Retry.InitialDelaySec {
+		c.YtDlp.Retry.MaxDelaySec = c.YtDlp.Retry.InitialDelaySec
+	}
 
 	// centraliser la résolution/normalisation de yt-dlp
 	c.ResolveYtDlpPath()
 }
 
+// GlossaryFiles : chemins des glossaires, les relatifs étant pris depuis le
+// dossier du fichier de configuration.
+func (c *Config) GlossaryFiles() []string {
+	out := make([]string, len(c.Glossary.Files))
+	for i, f := range c.Glossary.Files {
+		if !filepath.IsAbs(f) && c.configFilePath != "" {
+			f = filepath.Join(filepath.Dir(c.configFilePath), f)
+		}
+		out[i] = f
+	}
+	return out
+}
+
 // ResolveYtDlpPath normalise le nom et résout le chemin complet vers l'exécutable.
 // Appeler après avoir modifié cfg.YtDlp.Name ou cfg.YtDlp.Path.
 func (c *Config) ResolveYtDlpPath() {
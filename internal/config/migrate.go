@@ -99,7 +99,7 @@ func migrateConfig(cfg *Config, from int) error {
 		switch v {
 		case 0:
 			// migration 0 -> 1 : exemple (rien à faire pour l'instant)
-			// ex: if cfg.TranscriptFormat == "" { cfg.TranscriptFormat = "txt" }
+			// ex: if len(cfg.TranscriptFormats) == 0 { cfg.TranscriptFormats = StringList{"txt"} }
 		// case 1:
 		// migration 1 -> 2 : ...
 		default:
@@ -28,12 +28,48 @@ var (
 	ErrTooLarge = errors.New("response body too large")
 )
 
+// StatusError est renvoyée quand le serveur répond avec un code hors 2xx.
+// errors.Is(err, ErrStatus) reste vrai ; le code permet de distinguer un 404 attendu.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected http status %s", e.Status)
+}
+
+func (e *StatusError) Is(target error) bool { return target == ErrStatus }
+
+// IsStatus indique si err est une *StatusError avec le code donné.
+func IsStatus(err error, code int) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == code
+}
+
 // FetchBytesWithTimeout télécharge l'URL et retourne les octets.
 // - ctx peut être nil.
 // - timeout : si <=0 on utilise DefaultTimeout.
 // - maxBytes : si <=0 on utilise DefaultMaxBytes.
-// Note : cette fonction lit tout en mémoire (OK pour JSON youtube).
+// Note : cette fonction lit tout en mémoire (OK pour JSON youtube) ; voir Open
+// pour décoder au fil de l'eau.
 func FetchBytesWithTimeout(ctx context.Context, rawURL string, timeout time.Duration, maxBytes int64) ([]byte, error) {
+	body, err := Open(ctx, rawURL, timeout, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: read body: %w", err)
+	}
+	return data, nil
+}
+
+// Open lance la requête et retourne le corps de la réponse, à lire au fil de
+// l'eau puis à fermer (Close libère aussi le timeout). Mêmes défauts que
+// FetchBytesWithTimeout ; la lecture échoue avec ErrTooLarge au-delà de maxBytes.
+func Open(ctx context.Context, rawURL string, timeout time.Duration, maxBytes int64) (io.ReadCloser, error) {
 	// defaults
 	if ctx == nil {
 		ctx = context.Background()
@@ -50,12 +86,12 @@ func FetchBytesWithTimeout(ctx context.Context, rawURL string, timeout time.Dura
 		return nil, fmt.Errorf("fetch: invalid url %q: %w", rawURL, err)
 	}
 
-	// timeout via context
+	// timeout via context, annulé à la fermeture du corps
 	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("fetch: new request: %w", err)
 	}
 	req.Header.Set("User-Agent", DefaultUserAgent)
@@ -63,26 +99,49 @@ func FetchBytesWithTimeout(ctx context.Context, rawURL string, timeout time.Dura
 	client := &http.Client{} // pour tests on pourra passer un client en paramètre si besoin
 	resp, err := client.Do(req)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("fetch: request failed: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("fetch: unexpected http status %s", resp.Status)
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("fetch: %w", &StatusError{Code: resp.StatusCode, Status: resp.Status})
 	}
 
 	// si Content-Length connu et supérieur à maxBytes -> échouer vite
 	if resp.ContentLength > 0 && resp.ContentLength > maxBytes {
-		return nil, fmt.Errorf("fetch: content-length %d exceeds limit %d", resp.ContentLength, maxBytes)
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("fetch: content-length %d exceeds limit %d: %w", resp.ContentLength, maxBytes, ErrTooLarge)
 	}
+	return &limitedBody{body: resp.Body, left: maxBytes, cancel: cancel}, nil
+}
 
-	r := io.LimitReader(resp.Body, maxBytes+1) // +1 pour détecter dépassement
-	data, err := io.ReadAll(r)
-	if err != nil {
-		return nil, fmt.Errorf("fetch: read body: %w", err)
+// limitedBody : corps de réponse borné à left octets.
+type limitedBody struct {
+	body   io.ReadCloser
+	left   int64
+	cancel context.CancelFunc
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, ErrTooLarge
 	}
-	if int64(len(data)) > maxBytes {
-		return nil, fmt.Errorf("fetch: body too large (>%d bytes)", maxBytes)
+	// lire un octet de plus que la limite pour détecter le dépassement
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
 	}
-	return data, nil
+	n, err := l.body.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return n, fmt.Errorf("fetch: body too large: %w", ErrTooLarge)
+	}
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	defer l.cancel()
+	return l.body.Close()
 }
@@ -65,7 +65,7 @@ func FetchJSONInto(ctx context.Context, rawURL string, timeout time.Duration, ma
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("fetch json: unexpected http status %s", resp.Status)
+		return fmt.Errorf("fetch json: %w", &StatusError{Code: resp.StatusCode, Status: resp.Status})
 	}
 
 	if resp.ContentLength > 0 && maxBytes > 0 && resp.ContentLength > maxBytes {
@@ -0,0 +1,175 @@
+// Package glossary charge les glossaires de corrections de l'ASR (fichiers YAML)
+// et retient, pour une vidéo, les remplacements et termes connus qui la concernent.
+//
+// Un glossaire peut être limité à des chaînes (uploader) et à des langues, pour
+// tout le fichier ou pour un remplacement :
+//
+//	channels: ["Ma Chaîne"] # vide = toutes les chaînes
+//	langs: ["en"]           # vide = toutes les langues ; "en" couvre "en-US"
+//	terms: ["Kubernetes", "Grafana"]
+//	replacements:
+//	  - from: "cube and eighties"
+//	    to: "Kubernetes"
+//	  - from: '\bk(?:8|eight)s\b'
+//	    to: "Kubernetes"
+//	    match: regex # exact, ignore_case (défaut) ou regex
+package glossary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/patrickprogramme/subscribe/internal/subtitles"
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// scope : chaînes et langues concernées (vide = toutes).
+type scope struct {
+	Channels []string `yaml:"channels"`
+	Langs    []string `yaml:"langs"`
+}
+
+// matches : la vidéo de cette chaîne, dans cette langue, est concernée.
+func (s scope) matches(channel, lang string) bool {
+	return matchesAny(s.Channels, channel, false) && matchesAny(s.Langs, lang, true)
+}
+
+func matchesAny(list []string, v string, isLang bool) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, x := range list {
+		x = strings.TrimSpace(x)
+		if strings.EqualFold(x, v) || (isLang && strings.EqualFold(x, model.BaseLang(v))) {
+			return true
+		}
+	}
+	return false
+}
+
+// entry : un remplacement tel qu'écrit dans le fichier.
+type entry struct {
+	scope `yaml:",inline"`
+	From  string `yaml:"from"`
+	To    string `yaml:"to"`
+	Match string `yaml:"match"`
+}
+
+// file : un fichier de glossaire.
+type file struct {
+	scope        `yaml:",inline"`
+	Terms        []string `yaml:"terms"`
+	Replacements []entry  `yaml:"replacements"`
+}
+
+type rule struct {
+	file, own scope
+	rep       subtitles.Replacement
+}
+
+type term struct {
+	file, own scope
+	text      string
+}
+
+// Glossary : remplacements et termes de tous les fichiers chargés, dans l'ordre.
+type Glossary struct {
+	rules []rule
+	terms []term
+}
+
+// Selection : ce qui s'applique à une vidéo (voir Glossary.For).
+type Selection struct {
+	Replacements []subtitles.Replacement
+	// Terms : orthographe de référence (termes déclarés et cibles des remplacements), sans doublon
+	Terms []string
+}
+
+// Load lit les fichiers (chemins ou motifs, ex: "glossaries/*.yaml") dans l'ordre.
+// Un fichier absent ou invalide est une erreur.
+func Load(patterns []string) (*Glossary, error) {
+	g := &Glossary{}
+	for _, p := range patterns {
+		paths, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("glossaire %s: %w", p, err)
+		}
+		if len(paths) == 0 {
+			paths = []string{p}
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("lecture du glossaire %s impossible : %w", path, err)
+			}
+			if err := g.add(data); err != nil {
+				return nil, fmt.Errorf("glossaire %s: %w", path, err)
+			}
+		}
+	}
+	return g, nil
+}
+
+// Parse lit un glossaire YAML.
+func Parse(data []byte) (*Glossary, error) {
+	g := &Glossary{}
+	if err := g.add(data); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *Glossary) add(data []byte) error {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("analyse impossible : %w", err)
+	}
+	for _, t := range f.Terms {
+		if t = strings.TrimSpace(t); t != "" {
+			g.terms = append(g.terms, term{file: f.scope, text: t})
+		}
+	}
+	for i, e := range f.Replacements {
+		mode, err := subtitles.ParseMatchMode(e.Match)
+		if err != nil {
+			return fmt.Errorf("remplacement %d: %w", i+1, err)
+		}
+		rep, err := subtitles.NewReplacement(e.From, e.To, mode)
+		if err != nil {
+			return fmt.Errorf("remplacement %d: %w", i+1, err)
+		}
+		g.rules = append(g.rules, rule{file: f.scope, own: e.scope, rep: rep})
+		// la cible d'un remplacement est aussi un terme connu (sauf références $1...)
+		if to := strings.TrimSpace(e.To); to != "" && (mode != subtitles.MatchRegex || !strings.Contains(to, "$")) {
+			g.terms = append(g.terms, term{file: f.scope, own: e.scope, text: to})
+		}
+	}
+	return nil
+}
+
+// For retient les remplacements et termes de la chaîne channel (uploader) en langue lang.
+func (g *Glossary) For(channel, lang string) Selection {
+	var sel Selection
+	if g == nil {
+		return sel
+	}
+	for _, r := range g.rules {
+		if r.file.matches(channel, lang) && r.own.matches(channel, lang) {
+			sel.Replacements = append(sel.Replacements, r.rep)
+		}
+	}
+	seen := map[string]struct{}{}
+	for _, t := range g.terms {
+		key := strings.ToLower(t.text)
+		if _, dup := seen[key]; dup || !t.file.matches(channel, lang) || !t.own.matches(channel, lang) {
+			continue
+		}
+		seen[key] = struct{}{}
+		sel.Terms = append(sel.Terms, t.text)
+	}
+	return sel
+}
@@ -0,0 +1,66 @@
+package glossary
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGlossary = `
+terms: ["Grafana"]
+replacements:
+  - from: "cube and eighties"
+    to: "Kubernetes"
+  - from: "pat rick"
+    to: "Patrick"
+    channels: ["Ma Chaîne"]
+  - from: "cubernetesse"
+    to: "Kubernetes"
+    langs: ["fr"]
+  - from: '(\d+) pour cent'
+    to: "$1 %"
+    match: regex
+    langs: ["fr"]
+`
+
+func TestGlossaryFor(t *testing.T) {
+	g, err := Parse([]byte(testGlossary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		channel, lang string
+		wantFrom      []string
+		wantTerms     []string
+	}{
+		{"Autre", "en-US", []string{"cube and eighties"}, []string{"Grafana", "Kubernetes"}},
+		{"ma chaîne", "en", []string{"cube and eighties", "pat rick"}, []string{"Grafana", "Kubernetes", "Patrick"}},
+		{"Autre", "fr", []string{"cube and eighties", "cubernetesse", `(\d+) pour cent`}, []string{"Grafana", "Kubernetes"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.channel+"/"+tc.lang, func(t *testing.T) {
+			sel := g.For(tc.channel, tc.lang)
+			var from []string
+			for _, r := range sel.Replacements {
+				from = append(from, r.From)
+			}
+			if strings.Join(from, "|") != strings.Join(tc.wantFrom, "|") {
+				t.Errorf("remplacements = %q; want %q", from, tc.wantFrom)
+			}
+			if strings.Join(sel.Terms, "|") != strings.Join(tc.wantTerms, "|") {
+				t.Errorf("termes = %q; want %q", sel.Terms, tc.wantTerms)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{
+		"replacements:\n  - from: \"(\"\n    to: x\n    match: regex\n",
+		"replacements:\n  - from: a\n    to: b\n    match: fuzzy\n",
+		"replacements:\n  - from: \"  \"\n    to: b\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q) : erreur attendue", data)
+		}
+	}
+}
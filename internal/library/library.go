@@ -0,0 +1,120 @@
+// Package library lit les statistiques des vidéos déjà traitées (fichiers
+// "<titre> (stats).json" sous output_dir) et en tire les mots-clés TF-IDF d'un
+// transcript : les termes fréquents dans la vidéo mais rares dans le reste de
+// la bibliothèque.
+package library
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/internal/fsutil"
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+const statsSuffix = " (stats).json"
+
+// StatsFilename : "<titre> (stats).json"
+func StatsFilename(title string) string {
+	return fsutil.SanitizeFilename(title) + statsSuffix
+}
+
+// Load lit toutes les statistiques sous root, triées par titre ; un root absent
+// donne une bibliothèque vide. Un fichier illisible ou corrompu est ignoré :
+// docs contient les autres et err les liste (errors.Join). Une erreur de
+// parcours du dossier retourne docs nil.
+func Load(root string) (docs []model.TranscriptStats, err error) {
+	var bad []error
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), statsSuffix) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			bad = append(bad, err)
+			return nil
+		}
+		var s model.TranscriptStats
+		if err := json.Unmarshal(data, &s); err != nil {
+			bad = append(bad, fmt.Errorf("statistiques %s: %w", path, err))
+			return nil
+		}
+		docs = append(docs, s)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("lecture de la bibliothèque %s: %w", root, walkErr)
+	}
+	slices.SortFunc(docs, func(a, b model.TranscriptStats) int { return cmp.Compare(a.Title, b.Title) })
+	return docs, errors.Join(bad...)
+}
+
+// Keywords : les n termes de doc au meilleur TF-IDF face aux autres documents
+// de corpus (doc lui-même, reconnu par VideoID, est ignoré). Sans autre
+// document, ce sont les termes les plus fréquents.
+func Keywords(doc model.TranscriptStats, corpus []model.TranscriptStats, n int) []string {
+	if n <= 0 || len(doc.Terms) == 0 {
+		return nil
+	}
+	df := map[string]int{}
+	others := 0
+	for _, d := range corpus {
+		if d.VideoID != "" && d.VideoID == doc.VideoID {
+			continue
+		}
+		others++
+		for term := range d.Terms {
+			if _, ok := doc.Terms[term]; ok {
+				df[term]++
+			}
+		}
+	}
+	total := 0
+	for _, c := range doc.Terms {
+		total += c
+	}
+	type scored struct {
+		term  string
+		score float64
+	}
+	all := make([]scored, 0, len(doc.Terms))
+	for term, c := range doc.Terms {
+		// idf lissé : un terme présent partout garde un poids non nul
+		idf := math.Log(float64(1+others)/float64(1+df[term])) + 1
+		all = append(all, scored{term, float64(c) / float64(total) * idf})
+	}
+	slices.SortFunc(all, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.term, b.term))
+	})
+	out := make([]string, 0, min(n, len(all)))
+	for _, s := range all[:min(n, len(all))] {
+		out = append(out, s.term)
+	}
+	return out
+}
+
+// Find : documents dont le titre contient query (sans casse) ou dont l'identifiant vaut query.
+func Find(corpus []model.TranscriptStats, query string) []model.TranscriptStats {
+	q := strings.ToLower(strings.TrimSpace(query))
+	var out []model.TranscriptStats
+	for _, d := range corpus {
+		if d.VideoID == query || strings.Contains(strings.ToLower(d.Title), q) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
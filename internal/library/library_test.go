@@ -0,0 +1,63 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestKeywords(t *testing.T) {
+	doc := model.TranscriptStats{VideoID: "a", Terms: map[string]int{
+		"kubernetes": 6, "cluster": 4, "video": 8, "channel": 5,
+	}}
+	corpus := []model.TranscriptStats{
+		doc,
+		{VideoID: "b", Terms: map[string]int{"video": 3, "channel": 2, "python": 4}},
+		{VideoID: "c", Terms: map[string]int{"video": 5, "channel": 1, "cluster": 1}},
+	}
+	tests := []struct {
+		name   string
+		corpus []model.TranscriptStats
+		want   []string
+	}{
+		{"bibliothèque vide : fréquence", nil, []string{"video", "kubernetes", "channel"}},
+		{"termes rares en tête", corpus, []string{"kubernetes", "video", "cluster"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Keywords(doc, tc.corpus, 3); !slices.Equal(got, tc.want) {
+				t.Errorf("Keywords = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadSkipsCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("B (stats).json", `{"title": "B"}`)
+	write("A (stats).json", `{"title": "A"}`)
+	write("C (stats).json", `{"title": `)
+	write("notes.md", "pas des statistiques")
+
+	docs, err := Load(dir)
+	if err == nil || !strings.Contains(err.Error(), "C (stats).json") {
+		t.Errorf("erreur = %v; want le fichier corrompu", err)
+	}
+	if len(docs) != 2 || docs[0].Title != "A" || docs[1].Title != "B" {
+		t.Errorf("docs = %+v; want A, B", docs)
+	}
+
+	docs, err = Load(filepath.Join(dir, "absent"))
+	if err != nil || docs != nil {
+		t.Errorf("root absent : %v, %v", docs, err)
+	}
+}
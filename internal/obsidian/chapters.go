@@ -0,0 +1,99 @@
+package obsidian
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/internal/fsutil"
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// Découpage d'une longue vidéo (cours, conférence) en une note par chapitre
+// et une note parente qui les liste (split_notes).
+
+// wikilinkUnsafe : caractères qui cassent un [[lien]] Obsidian (ancre, bloc, alias).
+const wikilinkUnsafe = "#^[]|"
+
+// ChapterLink : un chapitre et le nom de sa note (sans ".md").
+type ChapterLink struct {
+	Chapter  model.Chapter
+	Filename string
+}
+
+// ChapterNoteData contient les données d'une note de chapitre.
+type ChapterNoteData struct {
+	Video      NoteData // note parente : titre, URL, auteur, tags... (Video.Filename pour le lien)
+	Chapter    model.Chapter
+	Index      int // rang du chapitre, à partir de 1
+	Total      int
+	Filename   string
+	Prev, Next *ChapterLink      // nil au premier / dernier chapitre
+	KeyMoments []model.KeyMoment // moments clés du chapitre
+	Transcript string            // phrases du chapitre (voir Transcript.ChapterSlice)
+}
+
+// SplitChapters prépare une note par chapitre de parent.Chapters : texts[i] est
+// le transcript du chapitre i. parent.ChapterNotes reçoit les liens vers ces
+// notes, et son nom de fichier perd les caractères interdits dans un lien.
+func SplitChapters(parent *NoteData, texts []string) []ChapterNoteData {
+	parent.Filename = wikilinkSafe(parent.Filename)
+	links := make([]ChapterLink, len(parent.Chapters))
+	for i, c := range parent.Chapters {
+		name := fsutil.SanitizeFilename(fmt.Sprintf("%s - %02d %s", parent.Filename, i+1, c.Title))
+		links[i] = ChapterLink{Chapter: c, Filename: wikilinkSafe(name)}
+	}
+	parent.ChapterNotes = links
+
+	notes := make([]ChapterNoteData, len(links))
+	for i, l := range links {
+		n := ChapterNoteData{
+			Video:    *parent,
+			Chapter:  l.Chapter,
+			Index:    i + 1,
+			Total:    len(links),
+			Filename: l.Filename,
+		}
+		if i > 0 {
+			n.Prev = &links[i-1]
+		}
+		if i+1 < len(links) {
+			n.Next = &links[i+1]
+		}
+		if i < len(texts) {
+			n.Transcript = texts[i]
+		}
+		for _, km := range parent.KeyMoments {
+			if km.ChapterIndex == i {
+				n.KeyMoments = append(n.KeyMoments, km)
+			}
+		}
+		notes[i] = n
+	}
+	return notes
+}
+
+// wikilinkSafe remplace les caractères qui cassent un lien [[...]].
+func wikilinkSafe(name string) string {
+	return stripRunes(name, wikilinkUnsafe)
+}
+
+// stripRunes remplace les runes de unsafe par des espaces et réduit les blancs.
+func stripRunes(s, unsafe string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(unsafe, r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// wikilinkPure : [[note|alias]] ; [[note]] si l'alias est vide. Seuls "[", "]"
+// et "|" sont retirés de l'alias ("C# ^2" reste lisible).
+func wikilinkPure(filename, alias string) string {
+	alias = stripRunes(alias, "[]|")
+	if alias == "" || alias == filename {
+		return "[[" + filename + "]]"
+	}
+	return "[[" + filename + "|" + alias + "]]"
+}
@@ -0,0 +1,102 @@
+package obsidian
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestSplitChapters(t *testing.T) {
+	parent := NoteData{
+		Filename: "Cours C# [partie 1]",
+		Chapters: []model.Chapter{
+			{Start: 0, Title: "Intro"},
+			{Start: 60, Title: "Types: int|float"},
+			{Start: 120, Title: "^Conclusion#"},
+		},
+		KeyMoments: []model.KeyMoment{
+			{TimestampMs: 1_000, ChapterIndex: 0},
+			{TimestampMs: 130_000, ChapterIndex: 2},
+			{TimestampMs: 125_000, ChapterIndex: 2},
+			{TimestampMs: 500, ChapterIndex: -1},
+		},
+	}
+	notes := SplitChapters(&parent, []string{"a", "b"})
+
+	if parent.Filename != "Cours C partie 1" {
+		t.Errorf("parent.Filename = %q", parent.Filename)
+	}
+	tests := []struct {
+		filename   string
+		prev, next string
+		moments    []int64
+		transcript string
+	}{
+		{"Cours C partie 1 - 01 Intro", "", "Cours C partie 1 - 02 Types- int float", []int64{1_000}, "a"},
+		{"Cours C partie 1 - 02 Types- int float", "Cours C partie 1 - 01 Intro", "Cours C partie 1 - 03 Conclusion", nil, "b"},
+		{"Cours C partie 1 - 03 Conclusion", "Cours C partie 1 - 02 Types- int float", "", []int64{130_000, 125_000}, ""},
+	}
+	if len(notes) != len(tests) || len(parent.ChapterNotes) != len(tests) {
+		t.Fatalf("got %d notes, %d liens; want %d", len(notes), len(parent.ChapterNotes), len(tests))
+	}
+	linkName := func(l *ChapterLink) string {
+		if l == nil {
+			return ""
+		}
+		return l.Filename
+	}
+	for i, tt := range tests {
+		n := notes[i]
+		if n.Filename != tt.filename || parent.ChapterNotes[i].Filename != tt.filename {
+			t.Errorf("note %d : Filename = %q, lien parent = %q; want %q", i, n.Filename, parent.ChapterNotes[i].Filename, tt.filename)
+		}
+		if linkName(n.Prev) != tt.prev || linkName(n.Next) != tt.next {
+			t.Errorf("note %d : prev/next = %q/%q; want %q/%q", i, linkName(n.Prev), linkName(n.Next), tt.prev, tt.next)
+		}
+		if n.Video.Filename != parent.Filename || n.Index != i+1 || n.Total != 3 {
+			t.Errorf("note %d : parent %q, %d/%d", i, n.Video.Filename, n.Index, n.Total)
+		}
+		var moments []int64
+		for _, km := range n.KeyMoments {
+			moments = append(moments, km.TimestampMs)
+		}
+		if !slices.Equal(moments, tt.moments) {
+			t.Errorf("note %d : moments = %v; want %v", i, moments, tt.moments)
+		}
+		if n.Transcript != tt.transcript {
+			t.Errorf("note %d : transcript = %q; want %q", i, n.Transcript, tt.transcript)
+		}
+	}
+}
+
+func TestWikilink(t *testing.T) {
+	tests := []struct {
+		filename, alias string
+		want            string
+	}{
+		{"Note", "", "[[Note]]"},
+		{"Note", "Note", "[[Note]]"},
+		{"Note - 01 Intro", "Intro", "[[Note - 01 Intro|Intro]]"},
+		{"Note", "C# ^2", "[[Note|C# ^2]]"},   // "#" et "^" restent lisibles dans l'alias
+		{"Note", "a|b [c]", "[[Note|a b c]]"}, // "|", "[" et "]" cassent le lien
+		{"Note", "[|]", "[[Note]]"},           // alias vide après nettoyage
+	}
+	for _, tt := range tests {
+		if got := wikilinkPure(tt.filename, tt.alias); got != tt.want {
+			t.Errorf("wikilinkPure(%q, %q) = %q; want %q", tt.filename, tt.alias, got, tt.want)
+		}
+	}
+
+	safe := []struct{ in, want string }{
+		{"Cours C#", "Cours C"},
+		{"a^b", "a b"},
+		{"[x] | y", "x y"},
+		{"déjà propre", "déjà propre"},
+	}
+	for _, tt := range safe {
+		if got := wikilinkSafe(tt.in); got != tt.want {
+			t.Errorf("wikilinkSafe(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -94,28 +94,100 @@ func markdownListPure(xs []string) string {
 	return b.String()
 }
 
+// timestampLinkPure : "[HH:MM:SS](url&t=Ns)", ou "HH:MM:SS" seul si baseURL est vide.
+func timestampLinkPure(s model.Seconds, baseURL string) string {
+	return s.Link(baseURL)
+}
+
 // formatChaptersPure : génère les lignes Markdown cliquables.
 // Si baseURL est vide, on produit des lignes sans lien.
 func formatChaptersPure(chs []model.Chapter, baseURL string) string {
 	if len(chs) == 0 {
 		return ""
 	}
-	sep := "?"
-	if strings.Contains(baseURL, "?") {
-		sep = "&"
-	}
 	var b strings.Builder
 	for _, c := range chs {
-		secs := int64(c.Start)
-		ts := c.Start.TimestampHHMMSS()
 		title := strings.TrimSpace(strings.ReplaceAll(c.Title, "\n", " "))
+		b.WriteString(fmt.Sprintf("- %s - %s\n", timestampLinkPure(c.Start, baseURL), title))
+	}
+	return b.String()
+}
 
-		if baseURL == "" {
-			b.WriteString(fmt.Sprintf("- %s - %s\n", ts, title))
-		} else {
-			link := fmt.Sprintf("%s%st=%ds", baseURL, sep, secs)
-			b.WriteString(fmt.Sprintf("- [%s](%s) - %s\n", ts, link, title))
+// formatCommentsPure : une ligne Markdown par commentaire, réponses indentées
+// selon leur profondeur (voir replyDepth).
+// Les timestamps cités dans le texte deviennent des liens vers la vidéo.
+func formatCommentsPure(comments []model.Comment, baseURL string) string {
+	if len(comments) == 0 {
+		return ""
+	}
+	parents := make(map[string]string, len(comments))
+	for _, c := range comments {
+		parents[c.ID] = c.ParentID
+	}
+	var b strings.Builder
+	for _, c := range comments {
+		text := strings.Join(strings.Fields(c.Text), " ")
+		text = model.ReplaceTimestamps(text, func(raw string, s model.Seconds) string {
+			if baseURL == "" {
+				return raw
+			}
+			return fmt.Sprintf("[%s](%s)", raw, s.URL(baseURL))
+		})
+
+		b.WriteString(strings.Repeat("  ", replyDepth(c, parents)))
+		b.WriteString("- ")
+		if c.Pinned {
+			b.WriteString("📌 ")
+		}
+		b.WriteString("**")
+		b.WriteString(strings.TrimSpace(c.Author))
+		b.WriteString("**")
+		if c.ByUploader {
+			b.WriteString(" (auteur)")
+		}
+		if c.Likes > 0 {
+			b.WriteString(fmt.Sprintf(" 👍 %d", c.Likes))
 		}
+		b.WriteString(" : ")
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// replyDepth : 0 pour un commentaire de premier niveau, 1 pour une réponse,
+// 2 pour une réponse à une réponse... en remontant les ParentID présents dans
+// parents. Un parent absent de la liste compte pour un niveau ; les cycles
+// sont coupés.
+func replyDepth(c model.Comment, parents map[string]string) int {
+	depth := 0
+	seen := map[string]bool{c.ID: true}
+	for id := c.ParentID; id != "" && !seen[id]; id = parents[id] {
+		seen[id] = true
+		depth++
+	}
+	return depth
+}
+
+// formatKeyMomentsPure : une ligne par moment clé, lien horodaté + chapitre + citation.
+func formatKeyMomentsPure(kms []model.KeyMoment, baseURL string) string {
+	if len(kms) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, k := range kms {
+		b.WriteString("- ")
+		b.WriteString(timestampLinkPure(k.Start(), baseURL))
+		if k.Chapter != "" {
+			b.WriteString(" — *")
+			b.WriteString(strings.TrimSpace(k.Chapter))
+			b.WriteString("*")
+		}
+		if q := strings.TrimSpace(k.Quote); q != "" {
+			b.WriteString(" : ")
+			b.WriteString(ensureQuoted(q))
+		}
+		b.WriteString("\n")
 	}
 	return b.String()
 }
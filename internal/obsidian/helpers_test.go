@@ -0,0 +1,23 @@
+package obsidian
+
+import (
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestFormatCommentsDepth(t *testing.T) {
+	comments := []model.Comment{
+		{ID: "a", Author: "Ana", Text: "top"},
+		{ID: "b", ParentID: "a", Author: "Ben", Text: "reply"},
+		{ID: "c", ParentID: "b", Author: "Cy", Text: "nested"},
+		{ID: "d", ParentID: "gone", Author: "Dee", Text: "orphan"}, // parent absent : un niveau
+	}
+	want := "- **Ana** : top\n" +
+		"  - **Ben** : reply\n" +
+		"    - **Cy** : nested\n" +
+		"  - **Dee** : orphan\n"
+	if got := formatCommentsPure(comments, ""); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -10,8 +10,6 @@ import (
 	"github.com/patrickprogramme/subscribe/pkg/model"
 )
 
-const baseYtURL = "https://www.youtube.com/watch?v="
-
 // rawTagRe : match un hashtag #suivi_dun_mot.
 // - capture (grp[1]) le texte sans le `#`
 // - autorise lettres Unicode (\p{L}), chiffres (\p{N}), underscore et tiret
@@ -30,8 +28,27 @@ type NoteData struct {
 	YtTags      []string
 	Description string
 	Chapters    []model.Chapter
-	Filename    string
-	Summary     string
+	Comments    []model.Comment // commentaires extraits (vide si désactivé)
+	Segments    []model.Segment // segments SponsorBlock (vide si désactivé)
+	// SponsorDuration : durée cumulée des segments, ex: "3 min 05 s" ("" si aucun)
+	SponsorDuration string
+	// KeyMoments : pics "les plus revus" rattachés au transcript (renseigné par l'app)
+	KeyMoments []model.KeyMoment
+	// Piste de sous-titres utilisée pour le transcript (renseigné par l'app)
+	SubtitleLang   string // ex: "en"
+	SubtitleSource string // "manual" ou "automatic"
+	SubtitleReason string // "original", "preferred", "fallback" ou "target"
+	// SubtitleTranslated : traduction automatique YouTube depuis SubtitleSourceLang
+	SubtitleTranslated bool
+	SubtitleSourceLang string
+	// ASR : qualité des captions auto face aux sous-titres manuels (asr_check, nil sinon)
+	ASR *model.ASRQuality
+	// Stats : statistiques du transcript (stats.enabled, nil sinon)
+	Stats *model.TranscriptStats
+	// ChapterNotes : notes de chapitre liées depuis la note parente (split_notes, vide sinon)
+	ChapterNotes []ChapterLink
+	Filename     string
+	Summary      string
 }
 
 func (n NoteData) DisplayHashtags() {
@@ -44,7 +61,7 @@ func (n NoteData) DisplayYtTags() {
 
 // NewNoteData construit NoteData à partir de model.Meta
 func NewNoteData(m *model.Meta, summary string) NoteData {
-	url := baseYtURL + m.ID
+	url := m.WatchURL()
 
 	var suffixe string
 	dateStr := "unknown"
@@ -65,19 +82,27 @@ func NewNoteData(m *model.Meta, summary string) NoteData {
 	filename := fmt.Sprintf("%s %s", base, suffixe)
 	t := fsutil.CapitalizeFirst(m.Title)
 
+	var sponsorDuration string
+	if total := model.TotalSegmentsMs(m.Segments); total > 0 {
+		sponsorDuration = model.Seconds(total / 1000).Human()
+	}
+
 	return NoteData{
-		URL:         url,
-		Title:       t,
-		Uploader:    m.Uploader,
-		DateStr:     dateStr,
-		Categories:  m.Categories,
-		Tags:        tags,
-		Hashtags:    hashtags,
-		YtTags:      m.YtTags,
-		Description: m.Description,
-		Chapters:    m.Chapters,
-		Filename:    filename,
-		Summary:     summary,
+		URL:             url,
+		Title:           t,
+		Uploader:        m.Uploader,
+		DateStr:         dateStr,
+		Categories:      m.Categories,
+		Tags:            tags,
+		Hashtags:        hashtags,
+		YtTags:          m.YtTags,
+		Description:     m.Description,
+		Chapters:        m.Chapters,
+		Comments:        m.Comments,
+		Segments:        m.Segments,
+		SponsorDuration: sponsorDuration,
+		Filename:        filename,
+		Summary:         summary,
 	}
 }
 
@@ -12,9 +12,6 @@ import (
 	"github.com/patrickprogramme/subscribe/pkg/model"
 )
 
-// TODO: ligne 54 : remplacer par un filtre,
-// par exemple tous les fichiers qui commencent par "obsidian"
-
 // Renderer gère parsing paresseux (lazy) des templates et fournit des méthodes de rendu.
 type Renderer struct {
 	templates *template.Template // templates parsés
@@ -46,10 +43,11 @@ func DefaultRenderer(exePath string) (*Renderer, error) {
 	binDir := filepath.Dir(exePath)
 	tplDir := filepath.Join(binDir, "templates")
 
-	// Lire les templates depuis le dossier à côté du binaire
+	// Lire les templates de notes (*.md.tmpl) depuis le dossier à côté du binaire :
+	// note unique, note parente et notes de chapitre (split_notes), ou templates perso
 	fsys := os.DirFS(tplDir)
 
-	r, err := NewRendererFromFS(fsys, []string{"obsidian_note.md.tmpl"})
+	r, err := NewRendererFromFS(fsys, []string{"*.md.tmpl"})
 	if err != nil {
 		return nil, err
 	}
@@ -90,9 +88,10 @@ func (r *Renderer) ParseNow() error {
 	return r.parseTemplates()
 }
 
-// Render exécute le template nommé tmplName (basename du fichier .tmpl) avec data.
+// Render exécute le template nommé tmplName (basename du fichier .tmpl) avec data
+// (NoteData, ou ChapterNoteData pour une note de chapitre).
 // Assure le parsing paresseux avant exécution.
-func (r *Renderer) Render(tmplName string, data NoteData) ([]byte, error) {
+func (r *Renderer) Render(tmplName string, data any) ([]byte, error) {
 	if r == nil {
 		return nil, fmt.Errorf("renderer is nil")
 	}
@@ -153,5 +152,17 @@ func baseFuncMap() template.FuncMap {
 		"formatChapters": func(chs []model.Chapter, baseURL string) string {
 			return formatChaptersPure(chs, baseURL)
 		},
+
+		// Comments formatter : usage {{ formatComments .Comments .URL }}
+		"formatComments": formatCommentsPure,
+
+		// Moments clés (heatmap) : usage {{ formatKeyMoments .KeyMoments .URL }}
+		"formatKeyMoments": formatKeyMomentsPure,
+
+		// Lien horodaté : usage {{ timestampLink .Start .URL }}
+		"timestampLink": timestampLinkPure,
+
+		// Lien interne Obsidian : usage {{ wikilink .Filename .Chapter.Title }}
+		"wikilink": wikilinkPure,
 	}
 }
@@ -0,0 +1,90 @@
+// Package sponsorblock récupère les segments SponsorBlock d'une vidéo
+// (sponsor, autopromo, intro, outro...) via internal/fetch.
+// L'URL de base de l'API est configurable pour pouvoir viser une instance locale.
+package sponsorblock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/patrickprogramme/subscribe/internal/fetch"
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+const (
+	DefaultAPIURL   = "https://sponsor.ajay.app"
+	defaultMaxBytes = 1_000_000
+)
+
+// DefaultCategories : catégories retirées/marquées par défaut dans les transcripts.
+var DefaultCategories = []string{"sponsor", "selfpromo", "intro", "outro"}
+
+// rawSegment : format renvoyé par GET /api/skipSegments
+type rawSegment struct {
+	Segment    [2]float64 `json:"segment"` // [début, fin] en secondes
+	UUID       string     `json:"UUID"`
+	Category   string     `json:"category"`
+	ActionType string     `json:"actionType"`
+}
+
+// SegmentsURL construit l'URL de l'API pour videoID et les catégories demandées.
+func SegmentsURL(baseURL, videoID string, categories []string) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if base == "" {
+		base = DefaultAPIURL
+	}
+	if videoID == "" {
+		return "", fmt.Errorf("sponsorblock: videoID vide")
+	}
+	if len(categories) == 0 {
+		categories = DefaultCategories
+	}
+	cats, err := json.Marshal(categories)
+	if err != nil {
+		return "", fmt.Errorf("sponsorblock: encodage des catégories: %w", err)
+	}
+	q := url.Values{}
+	q.Set("videoID", videoID)
+	q.Set("categories", string(cats))
+	return base + "/api/skipSegments?" + q.Encode(), nil
+}
+
+// FetchSegments télécharge les segments de videoID. Une vidéo sans segment
+// (HTTP 404 côté SponsorBlock) renvoie une slice vide et pas d'erreur.
+func FetchSegments(ctx context.Context, baseURL, videoID string, categories []string, timeout time.Duration) ([]model.Segment, error) {
+	u, err := SegmentsURL(baseURL, videoID, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := fetch.FetchJSON[[]rawSegment](ctx, u, timeout, defaultMaxBytes)
+	if err != nil {
+		if fetch.IsStatus(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("sponsorblock: %w", err)
+	}
+
+	out := make([]model.Segment, 0, len(raw))
+	for _, r := range raw {
+		start := int64(math.Round(r.Segment[0] * 1000))
+		end := int64(math.Round(r.Segment[1] * 1000))
+		if end <= start {
+			continue
+		}
+		out = append(out, model.Segment{
+			Category:   r.Category,
+			StartMs:    start,
+			EndMs:      end,
+			UUID:       r.UUID,
+			ActionType: r.ActionType,
+		})
+	}
+	return out, nil
+}
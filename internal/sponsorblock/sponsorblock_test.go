@@ -0,0 +1,39 @@
+package sponsorblock
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchSegments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/skipSegments" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("videoID") {
+		case "withsegs":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"segment":[12.5,42],"UUID":"u1","category":"sponsor","actionType":"skip"}]`))
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	segs, err := FetchSegments(context.Background(), srv.URL+"/", "withsegs", nil, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segs) != 1 || segs[0].StartMs != 12500 || segs[0].EndMs != 42000 || segs[0].Category != "sponsor" {
+		t.Fatalf("unexpected segments: %+v", segs)
+	}
+
+	segs, err = FetchSegments(context.Background(), srv.URL, "none", nil, time.Second)
+	if err != nil || len(segs) != 0 {
+		t.Fatalf("404 must yield no segments and no error, got %v, %v", segs, err)
+	}
+}
@@ -0,0 +1,164 @@
+package subtitles
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// abbreviations.go : abréviations qui ne terminent pas une phrase ("Dr.", "e.g.",
+// "M.", "z.B."...). Les listes intégrées sont en minuscules, sans le point final
+// ; une entrée d'une seule lettre est sensible à la casse ("M" = Monsieur, pas "m").
+
+var builtinAbbreviations = map[string][]string{
+	"en": {
+		"mr", "mrs", "ms", "dr", "prof", "st", "jr", "sr", "vs", "etc", "e.g", "i.e",
+		"cf", "approx", "vol", "fig", "inc", "ltd", "jan", "feb", "aug",
+		"sept", "oct", "nov", "dec",
+	},
+	"fr": {
+		"M", "mme", "mlle", "dr", "pr", "st", "ste", "etc", "cf", "p.ex", "c.-à-d",
+		"env", "av", "bd", "n°", "vol", "janv", "févr", "avr", "juil", "oct", "nov", "déc",
+	},
+	"de": {
+		"hr", "fr", "dr", "prof", "st", "z.b", "d.h", "u.a", "usw", "bzw", "ca", "vgl",
+		"evtl", "ggf", "inkl", "nr", "s", "jan", "feb", "okt", "nov", "dez",
+	},
+	"es": {
+		"sr", "sra", "srta", "dr", "dra", "d", "dña", "ud", "uds", "etc", "p.ej", "pág",
+		"núm", "aprox", "ej", "ene", "feb", "oct", "nov", "dic",
+	},
+}
+
+// sentenceFinalAbbreviations : abréviations souvent placées en fin de phrase ;
+// on coupe quand même après elles si le mot suivant commence par une majuscule.
+var sentenceFinalAbbreviations = map[string]struct{}{
+	"etc": {}, "usw": {}, "inc": {}, "ltd": {},
+}
+
+// BuiltinAbbreviations retourne la liste intégrée pour la langue (langue de base,
+// "fr-CA" -> "fr"), ou l'union de toutes les listes si la langue est inconnue.
+func BuiltinAbbreviations(lang string) []string {
+	if list, ok := builtinAbbreviations[model.BaseLang(lang)]; ok {
+		return list
+	}
+	var all []string
+	for _, l := range []string{"en", "fr", "de", "es"} {
+		all = append(all, builtinAbbreviations[l]...)
+	}
+	return all
+}
+
+// splitRules : règles de découpe résolues depuis Options (voir Options.splitRules).
+type splitRules struct {
+	abbreviations map[string]struct{} // nil : on coupe après toute abréviation
+	lang          string              // langue du texte (jointure et comptage des mots, voir scripts.go)
+}
+
+// splitRules résout la liste effective : intégrée pour opts.Lang + opts.Abbreviations.
+func (o Options) splitRules() splitRules {
+	if o.BreakOnAbbreviations {
+		return splitRules{lang: o.Lang}
+	}
+	set := make(map[string]struct{})
+	for _, a := range BuiltinAbbreviations(o.Lang) {
+		set[a] = struct{}{}
+	}
+	for a := range o.Abbreviations {
+		if a = normalizeAbbreviation(a); a != "" {
+			set[a] = struct{}{}
+		}
+	}
+	return splitRules{abbreviations: set, lang: o.Lang}
+}
+
+// normalizeAbbreviation : "Dr." -> "dr", "M." -> "M" (une lettre : casse conservée).
+func normalizeAbbreviation(s string) string {
+	s = strings.TrimSuffix(strings.TrimSpace(s), ".")
+	if utf8.RuneCountInString(s) <= 1 {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
+// isAbbreviation : word (sans le point final) figure dans la liste.
+func (r splitRules) isAbbreviation(word string) bool {
+	if r.abbreviations == nil || word == "" {
+		return false
+	}
+	// retirer la ponctuation ouvrante collée ("(cf", "«M")
+	word = strings.TrimLeftFunc(word, func(c rune) bool { return !unicode.IsLetter(c) && !unicode.IsDigit(c) })
+	_, ok := r.abbreviations[normalizeAbbreviation(word)]
+	return ok
+}
+
+// continuesSentence décide si la ponctuation `term` qui suit `prev` (dernier mot
+// avant la ponctuation) laisse la phrase ouverte. next est le mot suivant ("" en fin de texte).
+//   - points de suspension suivis d'une minuscule : "Wait... what?" reste une phrase ;
+//   - nombre suivi d'un point puis d'une minuscule : "le 3. mai", "Schritt 2. dann" ;
+//   - abréviation connue : "Dr. Smith", "e.g. this" (sauf "etc." suivi d'une majuscule).
+func (r splitRules) continuesSentence(prev, term, next string) bool {
+	next = strings.TrimLeftFunc(next, isOpenerRune) // "¿qué?", "«oui»"
+	nextRune, _ := utf8.DecodeRuneInString(next)
+	nextLower := next != "" && (unicode.IsLower(nextRune) || unicode.IsDigit(nextRune))
+
+	if strings.HasPrefix(term, "...") || strings.HasPrefix(term, "…") {
+		return nextLower
+	}
+	if term != "." {
+		return false
+	}
+	if isDigits(prev) {
+		return nextLower
+	}
+	if !r.isAbbreviation(prev) {
+		return false
+	}
+	if _, final := sentenceFinalAbbreviations[normalizeAbbreviation(prev)]; final {
+		return nextLower
+	}
+	return true
+}
+
+// endsOnAbbreviation : s se termine par "abréviation." sans mot suivant connu
+// (fin d'un seg ASR). Les points de suspension terminent la phrase.
+func (r splitRules) endsOnAbbreviation(s string) bool {
+	body := strings.TrimRight(s, ".!?")
+	return r.continuesSentence(lastField(body), s[len(body):], "")
+}
+
+// join recolle deux morceaux de phrase (sans espace pour le japonais, le chinois...).
+func (r splitRules) join(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + phraseJoiner(r.lang) + b
+}
+
+// countWords : voir CountWords.
+func (r splitRules) countWords(s string) int {
+	return CountWords(s, r.lang)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// lastField : dernier mot de s ("" si vide).
+func lastField(s string) string {
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+	if i := strings.LastIndexFunc(s, unicode.IsSpace); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
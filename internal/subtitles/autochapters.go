@@ -0,0 +1,289 @@
+package subtitles
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// autochapters.go : chapitres générés pour les vidéos qui n'en ont pas, par
+// segmentation thématique du transcript (à la TextTiling) :
+//   - les phrases sont regroupées en blocs d'au moins tileTerms termes (mots
+//     vides exclus, voir phraseTerms) ;
+//   - à chaque frontière de bloc, la cohésion lexicale est le cosinus entre les
+//     tileWindow blocs d'avant et ceux d'après ; un changement de sujet creuse
+//     une vallée, d'autant plus probable qu'elle est profonde (depth score) ;
+//   - une longue pause juste avant le bloc approfondit la vallée ;
+//   - les vallées les plus profondes, au-delà de moyenne - écart-type/2, deviennent
+//     des débuts de chapitre, espacés d'au moins MinChapterMs ;
+//   - le titre reprend les termes les plus propres au chapitre (TF-IDF entre chapitres).
+// Pas de chapitres générés en japonais, chinois... faute de découpage en mots.
+
+const (
+	tileTerms  = 20
+	tileWindow = 4
+	// profondeur ajoutée à une frontière précédée d'une longue pause
+	pauseDepthBonus = 0.1
+)
+
+// ChapterOptions : paramètres de GenerateChapters.
+type ChapterOptions struct {
+	Lang         string
+	MinChapterMs int64    // durée minimale d'un chapitre
+	PauseMs      int64    // pause qui renforce une frontière (0 = ignorée)
+	TitleTerms   int      // nombre de termes du titre
+	StopWords    []string // mots vides en plus des listes intégrées
+}
+
+// tile : bloc de phrases consécutives.
+type tile struct {
+	startMs int64
+	pause   int64 // silence avant le bloc
+	terms   map[string]int
+}
+
+// GenerateChapters découpe les phrases en chapitres (Source "generated").
+// nil si le transcript est trop court ou ne change pas de sujet.
+func GenerateChapters(phrases []Phrase, opts ChapterOptions) []model.Chapter {
+	if len(phrases) == 0 || IsSpacelessLang(opts.Lang) || opts.MinChapterMs <= 0 {
+		return nil
+	}
+	ends := phraseEnds(phrases)
+	endMs := slices.Max(ends)
+	if endMs < 2*opts.MinChapterMs {
+		return nil
+	}
+	stop := stopWordSet(opts.Lang, opts.StopWords)
+	forms := map[string]map[string]int{} // terme -> graphies rencontrées
+	tiles := buildTiles(phrases, ends, stop, forms)
+	if len(tiles) < 2*tileWindow {
+		return nil
+	}
+
+	depths := tileDepths(tiles, opts.PauseMs)
+	starts := pickBoundaries(tiles, depths, endMs, opts.MinChapterMs)
+	if len(starts) == 0 {
+		return nil
+	}
+
+	// chapitres : [0, starts[0]), [starts[0], starts[1])... en blocs
+	bounds := append([]int{0}, starts...)
+	segments := make([]map[string]int, len(bounds))
+	for i, b := range bounds {
+		end := len(tiles)
+		if i+1 < len(bounds) {
+			end = bounds[i+1]
+		}
+		segments[i] = map[string]int{}
+		for _, t := range tiles[b:end] {
+			for term, c := range t.terms {
+				segments[i][term] += c
+			}
+		}
+	}
+	chapters := make([]model.Chapter, len(bounds))
+	for i, b := range bounds {
+		start := tiles[b].startMs
+		if i == 0 {
+			start = 0
+		}
+		chapters[i] = model.Chapter{
+			Start:  model.Seconds(start / 1000),
+			Title:  chapterTitle(segments, i, forms, opts.TitleTerms),
+			Source: model.ChapterSourceGenerated,
+		}
+	}
+	model.FillChapterEnds(chapters, model.Seconds((endMs+999)/1000))
+	return chapters
+}
+
+// buildTiles regroupe les phrases en blocs d'au moins tileTerms termes et note
+// la graphie de chaque terme (pour les titres).
+func buildTiles(phrases []Phrase, ends []int64, stop map[string]struct{}, forms map[string]map[string]int) []tile {
+	var tiles []tile
+	var cur *tile
+	n := 0
+	for i, p := range phrases {
+		if cur == nil {
+			var pause int64
+			if i > 0 {
+				pause = p.TimestampMs - ends[i-1]
+			}
+			tiles = append(tiles, tile{startMs: p.TimestampMs, pause: pause, terms: map[string]int{}})
+			cur, n = &tiles[len(tiles)-1], 0
+		}
+		for _, f := range strings.Fields(p.Text) {
+			terms := phraseTerms(f, stop)
+			if len(terms) == 0 {
+				continue
+			}
+			term, form := terms[0], termForm(f)
+			cur.terms[term]++
+			n++
+			if forms[term] == nil {
+				forms[term] = map[string]int{}
+			}
+			forms[term][form]++
+		}
+		if n >= tileTerms {
+			cur = nil
+		}
+	}
+	// dernier bloc trop court : fusionné avec le précédent
+	if len(tiles) > 1 && cur != nil && n < tileTerms/2 {
+		last := tiles[len(tiles)-1]
+		tiles = tiles[:len(tiles)-1]
+		for term, c := range last.terms {
+			tiles[len(tiles)-1].terms[term] += c
+		}
+	}
+	return tiles
+}
+
+// tileDepths : profondeur de la vallée de cohésion avant chaque bloc (depths[0] = 0).
+func tileDepths(tiles []tile, pauseMs int64) []float64 {
+	sims := make([]float64, len(tiles))
+	for g := 1; g < len(tiles); g++ {
+		sims[g] = cosine(sumTerms(tiles[max(0, g-tileWindow):g]), sumTerms(tiles[g:min(len(tiles), g+tileWindow)]))
+	}
+	depths := make([]float64, len(tiles))
+	for g := 1; g < len(tiles); g++ {
+		left, right := sims[g], sims[g]
+		for j := g - 1; j >= 1 && sims[j] >= left; j-- {
+			left = sims[j]
+		}
+		for j := g + 1; j < len(tiles) && sims[j] >= right; j++ {
+			right = sims[j]
+		}
+		depths[g] = (left - sims[g]) + (right - sims[g])
+		if pauseMs > 0 && tiles[g].pause >= pauseMs {
+			depths[g] += pauseDepthBonus
+		}
+	}
+	return depths
+}
+
+// pickBoundaries retient les blocs qui ouvrent un chapitre : vallées les plus
+// profondes au-delà du seuil, à au moins minMs du début, de la fin et entre elles.
+func pickBoundaries(tiles []tile, depths []float64, endMs, minMs int64) []int {
+	var mean, sq float64
+	n := float64(len(depths) - 1)
+	for _, d := range depths[1:] {
+		mean += d
+	}
+	mean /= n
+	for _, d := range depths[1:] {
+		sq += (d - mean) * (d - mean)
+	}
+	cutoff := mean - math.Sqrt(sq/n)/2
+
+	candidates := make([]int, 0, len(tiles)-1)
+	for g := 1; g < len(tiles); g++ {
+		if depths[g] > 0 && depths[g] > cutoff {
+			candidates = append(candidates, g)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b int) int { return cmp.Compare(depths[b], depths[a]) })
+
+	var picked []int
+	for _, g := range candidates {
+		ts := tiles[g].startMs
+		if ts < minMs || endMs-ts < minMs {
+			continue
+		}
+		if slices.ContainsFunc(picked, func(p int) bool { return abs64(tiles[p].startMs-ts) < minMs }) {
+			continue
+		}
+		picked = append(picked, g)
+	}
+	slices.Sort(picked)
+	return picked
+}
+
+// chapterTitle : les n termes au meilleur TF-IDF du chapitre i face aux autres,
+// dans leur graphie la plus fréquente ("kubernetes" -> "Kubernetes").
+func chapterTitle(segments []map[string]int, i int, forms map[string]map[string]int, n int) string {
+	n = max(n, 1)
+	type scored struct {
+		term  string
+		score float64
+	}
+	var all []scored
+	for term, c := range segments[i] {
+		if c < 2 {
+			continue
+		}
+		df := 0
+		for _, s := range segments {
+			if s[term] > 0 {
+				df++
+			}
+		}
+		idf := math.Log(float64(1+len(segments))/float64(1+df)) + 1
+		all = append(all, scored{term, float64(c) * idf})
+	}
+	slices.SortFunc(all, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.term, b.term))
+	})
+	titles := make([]string, 0, n)
+	for _, s := range all[:min(n, len(all))] {
+		titles = append(titles, preferredForm(forms[s.term], s.term))
+	}
+	if len(titles) == 0 {
+		return fmt.Sprintf("Partie %d", i+1)
+	}
+	return capitalizeFirst(strings.Join(titles, ", "))
+}
+
+// preferredForm : graphie la plus fréquente, la minuscule à égalité.
+func preferredForm(forms map[string]int, fallback string) string {
+	best, bestN := fallback, 0
+	for f, c := range forms {
+		if c > bestN || (c == bestN && f > best) {
+			best, bestN = f, c
+		}
+	}
+	if bestN == 0 {
+		return fallback
+	}
+	return best
+}
+
+func sumTerms(tiles []tile) map[string]int {
+	out := map[string]int{}
+	for _, t := range tiles {
+		for term, c := range t.terms {
+			out[term] += c
+		}
+	}
+	return out
+}
+
+// cosine : similarité cosinus de deux vecteurs de fréquences.
+func cosine(a, b map[string]int) float64 {
+	var dot, na, nb float64
+	for term, x := range a {
+		na += float64(x * x)
+		if y, ok := b[term]; ok {
+			dot += float64(x * y)
+		}
+	}
+	for _, y := range b {
+		nb += float64(y * y)
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,56 @@
+package subtitles
+
+import (
+	"fmt"
+	"testing"
+)
+
+// topicPhrases : n phrases de 10 s sur un même sujet, à partir de startMs.
+func topicPhrases(startMs int64, n int, words ...string) []Phrase {
+	ps := make([]Phrase, n)
+	for i := range ps {
+		// les mots tournent pour varier les phrases
+		text := ""
+		for j := range 6 {
+			text += words[(i+j)%len(words)] + " "
+		}
+		ts := startMs + int64(i)*10_000
+		ps[i] = Phrase{TimestampMs: ts, EndMs: ts + 9_000, Text: text + "the end."}
+	}
+	return ps
+}
+
+func TestGenerateChapters(t *testing.T) {
+	var phrases []Phrase
+	phrases = append(phrases, topicPhrases(0, 30, "Kubernetes", "cluster", "pods", "nodes", "scheduler", "deployment")...)
+	phrases = append(phrases, topicPhrases(305_000, 30, "Python", "decorators", "functions", "closures", "generators", "iterators")...)
+	phrases = append(phrases, topicPhrases(610_000, 30, "sourdough", "bread", "flour", "starter", "oven", "crust")...)
+	opts := ChapterOptions{Lang: "en", MinChapterMs: 120_000, PauseMs: 3_000, TitleTerms: 2}
+
+	tests := []struct {
+		name    string
+		phrases []Phrase
+		want    string
+	}{
+		{"trois sujets", phrases, "[00:00:00 Cluster, deployment | 00:05:05 Closures, decorators | 00:10:10 Bread, crust]"},
+		{"trop court", phrases[:20], "[]"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chapters := GenerateChapters(tc.phrases, opts)
+			got := "["
+			for i, c := range chapters {
+				if !c.Generated() {
+					t.Errorf("chapitre %q non marqué comme généré", c.Title)
+				}
+				if i > 0 {
+					got += " | "
+				}
+				got += fmt.Sprintf("%s %s", c.Start.TimestampHHMMSS(), c.Title)
+			}
+			if got += "]"; got != tc.want {
+				t.Errorf("GenerateChapters = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package subtitles
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// syntheticJSON3 : json3 de `words` mots, comme un live de plusieurs heures.
+// auto : un seg par mot avec tOffsetMs et de rares ponctuations (captions ASR) ;
+// sinon un event par ligne de sous-titre manuel, phrases ponctuées.
+func syntheticJSON3(words int, auto bool) []byte {
+	vocab := strings.Fields("so the idea here is that we can build a small tool and then ship it to everyone who needs it today")
+	var b bytes.Buffer
+	b.WriteString(`{"wireMagic":"pb3","pens":[{}],"events":[`)
+	const perEvent = 8
+	const wordMs = 300
+	for ev := 0; ev*perEvent < words; ev++ {
+		if ev > 0 {
+			b.WriteByte(',')
+		}
+		start := int64(ev * perEvent * wordMs)
+		fmt.Fprintf(&b, `{"tStartMs":%d,"dDurationMs":%d,"wWinId":1,"segs":[`, start, perEvent*wordMs)
+		var line []string
+		for i := 0; i < perEvent; i++ {
+			w := vocab[(ev*perEvent+i)%len(vocab)]
+			if (ev*perEvent+i)%(3*len(vocab)) == len(vocab)-1 || (!auto && i == perEvent-1 && ev%2 == 1) {
+				w += "."
+			}
+			if auto {
+				if i > 0 {
+					b.WriteByte(',')
+					fmt.Fprintf(&b, `{"utf8":" %s","tOffsetMs":%d,"acAsrConf":0}`, w, i*wordMs)
+				} else {
+					fmt.Fprintf(&b, `{"utf8":"%s","acAsrConf":0}`, w)
+				}
+				continue
+			}
+			line = append(line, w)
+		}
+		if !auto {
+			fmt.Fprintf(&b, `{"utf8":"%s"}`, strings.Join(line, " "))
+		}
+		b.WriteString(`]}`)
+	}
+	b.WriteString(`]}`)
+	return b.Bytes()
+}
+
+// 3 h de parole à ~150 mots/min
+const benchWords = 3 * 60 * 150
+
+func BenchmarkParseJSON3Reader(b *testing.B) {
+	data := syntheticJSON3(benchWords, true)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ParseJSON3Reader(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTransformAuto(b *testing.B) {
+	raw, err := ParseJSON3Bytes(syntheticJSON3(benchWords, true))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := TransformAutoRawToPhrases(raw, Options{Lang: "en"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTransformManual(b *testing.B) {
+	raw, err := ParseJSON3Bytes(syntheticJSON3(benchWords, false))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := TransformManualRawToPhrases(raw, Options{Lang: "en"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Les compteurs tenus au fil des segs (RuneCount, WordCount, plafond
+// maxWordsPerPhrase) doivent égaler un recomptage complet du texte.
+func TestAutoPhrasesIncrementalCounts(t *testing.T) {
+	raw, err := ParseJSON3Bytes(syntheticJSON3(2_000, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	phrases, err := TransformAutoRawToPhrases(raw, Options{Lang: "en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for i, p := range phrases {
+		if p.RuneCount != len([]rune(p.Text)) || p.WordCount != CountWords(p.Text, "en") {
+			t.Fatalf("phrase %d : %d runes / %d mots, texte %q", i, p.RuneCount, p.WordCount, p.Text)
+		}
+		if p.WordCount > maxWordsPerPhrase {
+			t.Errorf("phrase %d : %d mots > %d", i, p.WordCount, maxWordsPerPhrase)
+		}
+		total += p.WordCount
+	}
+	if total != 2_000 {
+		t.Errorf("%d mots au total, want 2000", total)
+	}
+}
@@ -0,0 +1,170 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+const (
+	// durée supposée de la dernière phrase d'une piste (pas de phrase suivante)
+	lastPhraseMs = 5_000
+	// durée max d'une phrase : évite qu'un long silence n'absorbe toute l'autre piste
+	maxPhraseSpanMs = 15_000
+	// DefaultAlignToleranceMs : chevauchement minimal pour rattacher une phrase à une ligne
+	DefaultAlignToleranceMs = 300
+	// maxAlignRowMs : une ligne n'absorbe plus de phrase commençant au-delà
+	// (des découpes décalées ne se rejoignent parfois jamais)
+	maxAlignRowMs = 20_000
+)
+
+// BilingualLayout : mise en forme d'un transcript bilingue.
+type BilingualLayout string
+
+const (
+	BilingualTable       BilingualLayout = "table"       // tableau Markdown à deux colonnes
+	BilingualInterleaved BilingualLayout = "interleaved" // lignes alternées original / traduction
+)
+
+// ParseBilingualLayout convertit la valeur de config ("table" par défaut).
+func ParseBilingualLayout(s string) (BilingualLayout, error) {
+	switch s {
+	case "", "table":
+		return BilingualTable, nil
+	case "interleaved":
+		return BilingualInterleaved, nil
+	default:
+		return "", fmt.Errorf("mise en forme bilingue inconnue: %s", s)
+	}
+}
+
+// AlignedRow : phrases des deux pistes couvrant le même intervalle de temps.
+// Left ou Right peut être vide si une piste n'a rien sur cet intervalle.
+type AlignedRow struct {
+	StartMs int64
+	Left    []Phrase
+	Right   []Phrase
+}
+
+// LeftText / RightText : texte des phrases de la colonne, joint par un espace.
+func (r AlignedRow) LeftText() string  { return joinPhrases(r.Left) }
+func (r AlignedRow) RightText() string { return joinPhrases(r.Right) }
+
+func joinPhrases(ps []Phrase) string {
+	parts := make([]string, 0, len(ps))
+	for _, p := range ps {
+		parts = append(parts, strings.TrimSpace(p.displayText()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// phraseEnds calcule la fin de chaque phrase : Phrase.EndMs si connue, sinon
+// début de la suivante, bornée à maxPhraseSpanMs (la dernière dure lastPhraseMs).
+func phraseEnds(ps []Phrase) []int64 {
+	ends := make([]int64, len(ps))
+	for i, p := range ps {
+		if p.EndMs > p.TimestampMs {
+			ends[i] = p.EndMs
+			continue
+		}
+		end := p.TimestampMs + lastPhraseMs
+		if i+1 < len(ps) {
+			end = ps[i+1].TimestampMs
+		}
+		end = min(end, p.TimestampMs+maxPhraseSpanMs)
+		ends[i] = max(end, p.TimestampMs+1)
+	}
+	return ends
+}
+
+// AlignPhrases aligne deux pistes par chevauchement temporel.
+// Les deux pistes découpent rarement les phrases au même endroit : une ligne
+// commence par la phrase la plus précoce puis absorbe, d'un côté comme de l'autre,
+// toute phrase qui commence avant la fin courante de la ligne (moins toleranceMs),
+// jusqu'à ce que les deux côtés se rejoignent sur une frontière commune, ou que
+// la phrase suivante commence plus de maxAlignRowMs après le début de la ligne.
+// Les entrées sont triées si besoin, sans être modifiées.
+func AlignPhrases(left, right []Phrase, toleranceMs int64) []AlignedRow {
+	l := make([]Phrase, len(left))
+	copy(l, left)
+	ensureSortedPhrases(l)
+	r := make([]Phrase, len(right))
+	copy(r, right)
+	ensureSortedPhrases(r)
+	lEnds, rEnds := phraseEnds(l), phraseEnds(r)
+
+	var rows []AlignedRow
+	i, j := 0, 0
+	for i < len(l) || j < len(r) {
+		var row AlignedRow
+		var end int64
+		// la ligne démarre sur la phrase la plus précoce des deux pistes
+		if j >= len(r) || (i < len(l) && l[i].TimestampMs <= r[j].TimestampMs) {
+			row.StartMs, end = l[i].TimestampMs, lEnds[i]
+			row.Left = append(row.Left, l[i])
+			i++
+		} else {
+			row.StartMs, end = r[j].TimestampMs, rEnds[j]
+			row.Right = append(row.Right, r[j])
+			j++
+		}
+
+		limit := row.StartMs + maxAlignRowMs
+		for grown := true; grown; {
+			grown = false
+			if i < len(l) && l[i].TimestampMs < min(end-toleranceMs, limit) {
+				row.Left = append(row.Left, l[i])
+				end = max(end, lEnds[i])
+				i++
+				grown = true
+			}
+			if j < len(r) && r[j].TimestampMs < min(end-toleranceMs, limit) {
+				row.Right = append(row.Right, r[j])
+				end = max(end, rEnds[j])
+				j++
+				grown = true
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// RenderBilingual met en forme les lignes alignées, chaque ligne précédée d'un
+// lien horodaté vers baseURL (timestamp seul si baseURL est vide).
+func RenderBilingual(rows []AlignedRow, layout BilingualLayout, leftLang, rightLang, baseURL string) string {
+	var b strings.Builder
+	switch layout {
+	case BilingualInterleaved:
+		for _, row := range rows {
+			fmt.Fprintf(&b, "%s\n", timestampLink(row.StartMs, baseURL))
+			if t := row.LeftText(); t != "" {
+				fmt.Fprintf(&b, "**%s** %s\n", leftLang, t)
+			}
+			if t := row.RightText(); t != "" {
+				fmt.Fprintf(&b, "*%s* %s\n", rightLang, t)
+			}
+			b.WriteString("\n")
+		}
+	default:
+		fmt.Fprintf(&b, "| | %s | %s |\n", leftLang, rightLang)
+		b.WriteString("| --- | --- | --- |\n")
+		for _, row := range rows {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", timestampLink(row.StartMs, baseURL),
+				escapeCell(row.LeftText()), escapeCell(row.RightText()))
+		}
+	}
+	return b.String()
+}
+
+// escapeCell protège une cellule de tableau Markdown.
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// timestampLink : lien horodaté vers baseURL (voir model.Seconds.Link).
+func timestampLink(ms int64, baseURL string) string {
+	return model.Seconds(ms / 1000).Link(baseURL)
+}
@@ -0,0 +1,68 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlignPhrases(t *testing.T) {
+	ph := func(ms int64, text string) Phrase { return Phrase{TimestampMs: ms, Text: text} }
+
+	// gauche : 3 phrases ; droite : la 1re et la 2e sont fusionnées, la 3e est coupée en deux
+	left := []Phrase{ph(0, "A1."), ph(2000, "A2."), ph(5000, "A3."), ph(20000, "A4.")}
+	right := []Phrase{ph(100, "B1 B2."), ph(5050, "B3a,"), ph(6500, "B3b."), ph(20100, "B4.")}
+
+	rows := AlignPhrases(left, right, DefaultAlignToleranceMs)
+	want := [][2]string{
+		{"A1. A2.", "B1 B2."},
+		{"A3.", "B3a, B3b."},
+		{"A4.", "B4."},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		if rows[i].LeftText() != w[0] || rows[i].RightText() != w[1] {
+			t.Errorf("row %d = (%q, %q), want (%q, %q)", i, rows[i].LeftText(), rows[i].RightText(), w[0], w[1])
+		}
+	}
+
+	// une piste vide : une ligne par phrase de l'autre piste
+	if rows := AlignPhrases(left, nil, DefaultAlignToleranceMs); len(rows) != len(left) {
+		t.Errorf("sans seconde piste : %d rows, want %d", len(rows), len(left))
+	}
+}
+
+func TestAlignPhrasesStaggered(t *testing.T) {
+	// découpes décalées d'une seconde sur 2 min : les frontières ne se rejoignent jamais
+	var left, right []Phrase
+	for ms := int64(0); ms < 120_000; ms += 2000 {
+		left = append(left, Phrase{TimestampMs: ms, EndMs: ms + 2000, Text: "a"})
+		right = append(right, Phrase{TimestampMs: ms + 1000, EndMs: ms + 3000, Text: "b"})
+	}
+	rows := AlignPhrases(left, right, DefaultAlignToleranceMs)
+	if len(rows) < 120_000/maxAlignRowMs {
+		t.Fatalf("got %d rows : découpes enchaînées en lignes géantes", len(rows))
+	}
+	nl, nr := 0, 0
+	for i, row := range rows {
+		nl += len(row.Left)
+		nr += len(row.Right)
+		for _, p := range append(row.Left, row.Right...) {
+			if p.TimestampMs-row.StartMs >= maxAlignRowMs {
+				t.Errorf("row %d (%d ms) absorbe une phrase à %d ms", i, row.StartMs, p.TimestampMs)
+			}
+		}
+	}
+	if nl != len(left) || nr != len(right) {
+		t.Errorf("phrases alignées : %d/%d, %d/%d", nl, len(left), nr, len(right))
+	}
+}
+
+func TestRenderBilingualTable(t *testing.T) {
+	rows := []AlignedRow{{StartMs: 61000, Left: []Phrase{{Text: "a | b"}}, Right: []Phrase{{Text: "c"}}}}
+	got := RenderBilingual(rows, BilingualTable, "fr", "en", "https://www.youtube.com/watch?v=x")
+	if !strings.Contains(got, "| [00:01:01](https://www.youtube.com/watch?v=x&t=61s) | a \\| b | c |") {
+		t.Errorf("table inattendue:\n%s", got)
+	}
+}
@@ -0,0 +1,352 @@
+package subtitles
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// cleanup.go : nettoyage du bruit ASR après la construction des phrases.
+//   - balises sonores : [Music], [Applause], [Rires], ♪ ;
+//   - mots censurés : [ __ ] ;
+//   - bégaiements : "the the" -> "the" ;
+//   - mots de remplissage par langue : "um", "uh", "euh", "du coup"...
+//
+// Le mode strict ne retire que les balises sonores (ce ne sont pas des mots) :
+// les citations restent fidèles au texte prononcé.
+
+// CleanupOptions : étapes du nettoyage (voir CleanPhrases).
+type CleanupOptions struct {
+	Lang         string   // langue du texte (liste de remplissage, exceptions de bégaiement)
+	Strict       bool     // ne jamais modifier les mots : balises sonores uniquement
+	SoundTags    bool     // retirer [Music], [Applause]...
+	Censored     bool     // retirer [ __ ]
+	Stutters     bool     // retirer les mots répétés
+	Fillers      bool     // retirer les mots de remplissage
+	ExtraFillers []string // mots de remplissage en plus de la liste intégrée ("genre", "like")
+}
+
+// DefaultCleanupOptions : toutes les étapes, mode non strict.
+func DefaultCleanupOptions() CleanupOptions {
+	return CleanupOptions{SoundTags: true, Censored: true, Stutters: true, Fillers: true}
+}
+
+// mots de remplissage par langue ; une entrée de plusieurs mots est une suite ("du coup")
+var builtinFillers = map[string][]string{
+	"en": {"um", "umm", "uh", "uhh", "uhm", "erm", "hmm", "mm", "mhm"},
+	"fr": {"euh", "euhh", "heu", "hum", "hmm", "bah", "du coup"},
+	"de": {"äh", "ähm", "öh", "öhm", "hm", "hmm"},
+	"es": {"eh", "em", "mmm", "ehh"},
+}
+
+// répétitions légitimes ("nous nous sommes", "I know that that is")
+var stutterExceptions = map[string][]string{
+	"en": {"that", "had", "is", "bye", "very", "no", "yes"},
+	"fr": {"nous", "vous", "très", "non", "oui"},
+	"de": {"die", "das", "sie", "nein", "ja"},
+	"es": {"no", "sí", "muy"},
+}
+
+// CleanupReport : ce qui a été retiré.
+type CleanupReport struct {
+	SoundTags map[string]int // balise (texte entre crochets) -> occurrences
+	Censored  int
+	Stutters  map[string]int // mot répété -> occurrences retirées
+	Fillers   map[string]int
+	Dropped   int // phrases devenues vides, supprimées
+}
+
+// Total : nombre d'éléments retirés (hors phrases supprimées).
+func (r CleanupReport) Total() int {
+	n := r.Censored
+	for _, m := range []map[string]int{r.SoundTags, r.Stutters, r.Fillers} {
+		for _, c := range m {
+			n += c
+		}
+	}
+	return n
+}
+
+// String : "balises sonores : [Music] ×3 ; bégaiements : the ×2 ; ..." ou "rien retiré".
+func (r CleanupReport) String() string {
+	var parts []string
+	add := func(label string, m map[string]int) {
+		if len(m) == 0 {
+			return
+		}
+		items := make([]string, 0, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			items = append(items, fmt.Sprintf("%s ×%d", k, m[k]))
+		}
+		parts = append(parts, label+" : "+strings.Join(items, ", "))
+	}
+	add("balises sonores", r.SoundTags)
+	if r.Censored > 0 {
+		parts = append(parts, fmt.Sprintf("mots censurés : %d", r.Censored))
+	}
+	add("bégaiements", r.Stutters)
+	add("remplissage", r.Fillers)
+	if r.Dropped > 0 {
+		parts = append(parts, fmt.Sprintf("phrases vides supprimées : %d", r.Dropped))
+	}
+	if len(parts) == 0 {
+		return "rien retiré"
+	}
+	return strings.Join(parts, " ; ")
+}
+
+// CleanPhrases applique le nettoyage et retourne les phrases nettoyées (copie)
+// et le rapport. Les timings par mot sont conservés pour les mots gardés.
+func CleanPhrases(phrases []Phrase, opts CleanupOptions) ([]Phrase, CleanupReport) {
+	rep := CleanupReport{
+		SoundTags: map[string]int{},
+		Stutters:  map[string]int{},
+		Fillers:   map[string]int{},
+	}
+	if opts.Strict {
+		opts.Censored, opts.Stutters, opts.Fillers = false, false, false
+	}
+	fillers := fillerSequences(opts)
+	exceptions := lexicon(stutterExceptions, opts.Lang)
+
+	out := make([]Phrase, 0, len(phrases))
+	for _, p := range phrases {
+		tokens, timed := phraseTokens(p)
+		tokens = removeBracketGroups(tokens, opts, &rep)
+		if opts.Fillers {
+			tokens = removeFillers(tokens, fillers, &rep)
+		}
+		if opts.Stutters {
+			tokens = removeStutters(tokens, exceptions, &rep)
+		}
+		if len(tokens) == 0 {
+			rep.Dropped++
+			continue
+		}
+		out = append(out, rebuildPhrase(p, tokens, timed, opts.Lang))
+	}
+	return out, rep
+}
+
+// cleanToken : un mot de la phrase ; capitalized : le premier mot a été retiré,
+// celui-ci reprend sa majuscule.
+type cleanToken struct {
+	Word
+	capitalized bool
+}
+
+// phraseTokens : mots de la phrase (Words si présents, sinon le texte découpé).
+func phraseTokens(p Phrase) ([]cleanToken, bool) {
+	var tokens []cleanToken
+	if len(p.Words) > 0 && wordsMatchText(p) {
+		for _, w := range p.Words {
+			tokens = append(tokens, cleanToken{Word: w})
+		}
+		return tokens, true
+	}
+	for _, f := range strings.Fields(p.Text) {
+		tokens = append(tokens, cleanToken{Word: Word{Text: f, StartMs: p.TimestampMs, EndMs: p.TimestampMs}})
+	}
+	return tokens, false
+}
+
+// wordsMatchText : Words reflète bien le texte (un mot ASR par champ).
+func wordsMatchText(p Phrase) bool {
+	return len(strings.Fields(p.Text)) == len(p.Words)
+}
+
+// removeBracketGroups retire les balises sonores ("[Music]", "[Musique douce]", "♪")
+// et les mots censurés ("[ __ ]") selon opts.
+func removeBracketGroups(tokens []cleanToken, opts CleanupOptions, rep *CleanupReport) []cleanToken {
+	out := tokens[:0:0]
+	for i := 0; i < len(tokens); i++ {
+		t := strings.TrimSpace(tokens[i].Text)
+		if strings.Trim(t, "♪♫ ") == "" {
+			if opts.SoundTags {
+				rep.SoundTags["♪"]++
+				continue
+			}
+			out = append(out, tokens[i])
+			continue
+		}
+		if !strings.HasPrefix(t, "[") {
+			out = append(out, tokens[i])
+			continue
+		}
+		// groupe jusqu'au token qui ferme le crochet
+		j := i
+		for j < len(tokens) && !strings.Contains(tokens[j].Text, "]") {
+			j++
+		}
+		if j == len(tokens) {
+			out = append(out, tokens[i])
+			continue
+		}
+		parts := make([]string, 0, j-i+1)
+		for _, tk := range tokens[i : j+1] {
+			parts = append(parts, strings.TrimSpace(tk.Text))
+		}
+		group := strings.Join(parts, " ")
+		inner := strings.TrimSpace(strings.Trim(group[:strings.LastIndex(group, "]")+1], "[]"))
+		censored := strings.Trim(inner, "_ ") == ""
+		switch {
+		case censored && opts.Censored:
+			rep.Censored++
+		case !censored && opts.SoundTags:
+			rep.SoundTags["["+inner+"]"]++
+		default:
+			out = append(out, tokens[i:j+1]...)
+		}
+		i = j
+	}
+	return out
+}
+
+// fillerSequences : liste intégrée pour opts.Lang + ExtraFillers, découpées en mots.
+func fillerSequences(opts CleanupOptions) [][]string {
+	list, ok := builtinFillers[model.BaseLang(opts.Lang)]
+	if !ok {
+		for _, l := range slices.Sorted(maps.Keys(builtinFillers)) {
+			list = append(list, builtinFillers[l]...)
+		}
+	}
+	var seqs [][]string
+	for _, f := range append(slices.Clone(list), opts.ExtraFillers...) {
+		if ws := strings.Fields(strings.ToLower(f)); len(ws) > 0 {
+			seqs = append(seqs, ws)
+		}
+	}
+	// les suites les plus longues d'abord ("du coup" avant un éventuel "du")
+	slices.SortStableFunc(seqs, func(a, b []string) int { return len(b) - len(a) })
+	return seqs
+}
+
+// removeFillers retire les suites de remplissage ; une ponctuation finale portée
+// par le mot retiré ("euh.") passe au mot gardé précédent.
+func removeFillers(tokens []cleanToken, fillers [][]string, rep *CleanupReport) []cleanToken {
+	out := tokens[:0:0]
+	for i := 0; i < len(tokens); {
+		n := matchFiller(tokens[i:], fillers)
+		if n == 0 {
+			out = append(out, tokens[i])
+			i++
+			continue
+		}
+		removed := tokens[i : i+n]
+		label := make([]string, 0, n)
+		for _, tk := range removed {
+			label = append(label, bareWord(tk.Text))
+		}
+		rep.Fillers[strings.Join(label, " ")]++
+		carryPunctuation(out, removed[n-1].Text)
+		if i == 0 && startsUpper(removed[0].Text) && i+n < len(tokens) {
+			tokens[i+n].capitalized = true
+		}
+		i += n
+	}
+	return out
+}
+
+// matchFiller : longueur de la suite de remplissage au début de tokens (0 si aucune).
+// Une suite de plusieurs mots ne doit pas être coupée par une ponctuation interne.
+func matchFiller(tokens []cleanToken, fillers [][]string) int {
+	for _, seq := range fillers {
+		if len(seq) > len(tokens) {
+			continue
+		}
+		ok := true
+		for k, w := range seq {
+			if bareWord(tokens[k].Text) != w || (k < len(seq)-1 && trailingPunct(tokens[k].Text) != "") {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return len(seq)
+		}
+	}
+	return 0
+}
+
+// removeStutters retire la première occurrence d'un mot répété immédiatement
+// ("the the" -> "the"), sauf exceptions et si le premier porte une ponctuation.
+func removeStutters(tokens []cleanToken, exceptions map[string]struct{}, rep *CleanupReport) []cleanToken {
+	out := tokens[:0:0]
+	for i, tk := range tokens {
+		if i+1 < len(tokens) {
+			w := bareWord(tk.Text)
+			_, except := exceptions[w]
+			if w != "" && !except && trailingPunct(tk.Text) == "" && w == bareWord(tokens[i+1].Text) {
+				rep.Stutters[w]++
+				if i == 0 && startsUpper(tk.Text) {
+					tokens[i+1].capitalized = true
+				}
+				continue
+			}
+		}
+		out = append(out, tk)
+	}
+	return out
+}
+
+// trailingPunct : ponctuation en fin de mot ("word," -> ",").
+func trailingPunct(s string) string {
+	trimmed := strings.TrimRightFunc(s, func(r rune) bool { return unicode.IsPunct(r) && r != '\'' })
+	return s[len(trimmed):]
+}
+
+// carryPunctuation reporte la ponctuation finale d'un mot retiré sur le dernier mot gardé.
+func carryPunctuation(kept []cleanToken, removed string) {
+	if len(kept) == 0 {
+		return
+	}
+	p := trailingPunct(removed)
+	r, _ := utf8.DecodeRuneInString(p)
+	if p == "" || !isSentenceTerminatorRune(r) {
+		return
+	}
+	last := &kept[len(kept)-1]
+	last.Text = strings.TrimRightFunc(last.Text, unicode.IsPunct) + p
+}
+
+func startsUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return unicode.IsUpper(r)
+		}
+	}
+	return false
+}
+
+// rebuildPhrase reconstruit texte, bornes et compteurs à partir des mots gardés.
+func rebuildPhrase(p Phrase, tokens []cleanToken, timed bool, lang string) Phrase {
+	text := p.Text
+	texts := make([]string, len(tokens))
+	words := make([]Word, len(tokens))
+	for i, tk := range tokens {
+		if tk.capitalized {
+			tk.Text = capitalizeFirst(tk.Text)
+		}
+		texts[i] = strings.TrimSpace(tk.Text)
+		words[i] = tk.Word
+		words[i].Text = texts[i]
+	}
+	p.Text = normalizeWhitespace(strings.Join(texts, phraseJoiner(lang)))
+	p.RuneCount = utf8.RuneCountInString(p.Text)
+	p.WordCount = CountWords(p.Text, lang)
+	switch {
+	case timed:
+		p.Words = words
+		p.TimestampMs = words[0].StartMs
+	case len(tokens) != len(strings.Fields(text)):
+		// Words ne suivait pas le texte : on ne sait pas quels timings retirer,
+		// mieux vaut aucun timing que des mots retirés qui reviennent dans le json
+		p.Words = nil
+	}
+	return p
+}
@@ -0,0 +1,80 @@
+package subtitles
+
+import "testing"
+
+func TestCleanPhrases(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CleanupOptions
+		in   string
+		want string // "" : phrase supprimée
+	}{
+		{"balise sonore", CleanupOptions{SoundTags: true}, "[Music] so we start", "so we start"},
+		{"balise multi-mots", CleanupOptions{SoundTags: true}, "on y va [Musique douce] maintenant.", "on y va maintenant."},
+		{"phrase vide", CleanupOptions{SoundTags: true}, "[Applause]", ""},
+		{"notes de musique", CleanupOptions{SoundTags: true}, "♪ la la ♪", "la la"},
+		{"censuré", CleanupOptions{Censored: true}, "what the [ __ ] is that", "what the is that"},
+		{"bégaiement", CleanupOptions{Stutters: true}, "I think the the answer is", "I think the answer is"},
+		{"répétition légitime", CleanupOptions{Lang: "fr", Stutters: true}, "nous nous sommes vus", "nous nous sommes vus"},
+		{"remplissage en tête", CleanupOptions{Lang: "en", Fillers: true}, "Um, so we start.", "So we start."},
+		{"remplissage fin de phrase", CleanupOptions{Lang: "fr", Fillers: true}, "c'est prêt euh.", "c'est prêt."},
+		{"remplissage multi-mots", CleanupOptions{Lang: "fr", Fillers: true}, "et du coup on part", "et on part"},
+		{"remplissage ajouté", CleanupOptions{Lang: "fr", Fillers: true, ExtraFillers: []string{"genre"}}, "c'est genre énorme", "c'est énorme"},
+		{
+			"strict : seules les balises sonores",
+			CleanupOptions{Lang: "en", Strict: true, SoundTags: true, Censored: true, Stutters: true, Fillers: true},
+			"[Music] um the the [ __ ] thing",
+			"um the the [ __ ] thing",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, rep := CleanPhrases([]Phrase{{TimestampMs: 1000, Text: tc.in}}, tc.opts)
+			if tc.want == "" {
+				if len(got) != 0 || rep.Dropped != 1 {
+					t.Fatalf("phrase non supprimée : %#v", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Text != tc.want {
+				t.Fatalf("got %#v; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCleanPhrasesReportAndTimings(t *testing.T) {
+	p := Phrase{TimestampMs: 0, EndMs: 2000, Text: "uh the the plan [Music]", Words: []Word{
+		{Text: "uh", StartMs: 0, EndMs: 300},
+		{Text: "the", StartMs: 300, EndMs: 600},
+		{Text: "the", StartMs: 600, EndMs: 900},
+		{Text: "plan", StartMs: 900, EndMs: 1400},
+		{Text: "[Music]", StartMs: 1400, EndMs: 2000},
+	}}
+	got, rep := CleanPhrases([]Phrase{p}, CleanupOptions{Lang: "en", SoundTags: true, Stutters: true, Fillers: true})
+	if len(got) != 1 || got[0].Text != "the plan" || got[0].TimestampMs != 600 || len(got[0].Words) != 2 {
+		t.Fatalf("got %#v", got)
+	}
+	if rep.Total() != 3 || rep.Fillers["uh"] != 1 || rep.Stutters["the"] != 1 || rep.SoundTags["[Music]"] != 1 {
+		t.Errorf("rapport = %+v", rep)
+	}
+	want := "balises sonores : [Music] ×1 ; bégaiements : the ×1 ; remplissage : uh ×1"
+	if rep.String() != want {
+		t.Errorf("String() = %q; want %q", rep.String(), want)
+	}
+}
+
+func TestCleanPhrasesUntimedWords(t *testing.T) {
+	// Words ne correspond pas au texte (un mot ASR regroupe "the plan") : timings abandonnés
+	p := Phrase{Text: "uh the plan", Words: []Word{{Text: "uh"}, {Text: "the plan"}}}
+	got, _ := CleanPhrases([]Phrase{p}, CleanupOptions{Lang: "en", Fillers: true})
+	if len(got) != 1 || got[0].Text != "the plan" || got[0].Words != nil {
+		t.Fatalf("got %#v", got)
+	}
+	// rien retiré : Words est gardé
+	p = Phrase{Text: "so the plan", Words: []Word{{Text: "so"}, {Text: "the plan"}}}
+	got, _ = CleanPhrases([]Phrase{p}, CleanupOptions{Lang: "en", Fillers: true})
+	if len(got) != 1 || len(got[0].Words) != 2 {
+		t.Fatalf("Words perdus sans nettoyage : %#v", got)
+	}
+}
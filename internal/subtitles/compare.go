@@ -0,0 +1,255 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// compare.go : WER (taux d'erreur de mots) des captions auto face aux sous-titres
+// manuels de la même vidéo, et diff mot à mot.
+//   - les mots sont comparés en minuscules, sans la ponctuation qui les entoure ;
+//     en japonais, chinois, thaï... chaque caractère compte pour un mot (CER) ;
+//   - l'alignement (Levenshtein) se fait par blocs de compareChunkWords mots de
+//     référence ; chaque bloc n'est validé que jusqu'à son dernier mot identique
+//     (ancre), le reste est réaligné avec le bloc suivant : coût linéaire même
+//     pour un live de plusieurs heures.
+
+const (
+	compareChunkWords = 200
+	// marge de temps pour prendre les mots des captions d'un bloc (décalage de timing)
+	compareSlackMs = 5_000
+	// mots identiques gardés autour d'une différence dans le rapport
+	diffContextWords = 3
+)
+
+// DiffKind : nature d'une opération du diff.
+type DiffKind int
+
+const (
+	DiffEqual DiffKind = iota
+	DiffSubstitute
+	DiffDelete // mot des sous-titres manuels absent des captions
+	DiffInsert // mot des captions absent des sous-titres manuels
+)
+
+// DiffOp : une opération du diff mot à mot (Ref et/ou Hyp selon Kind).
+type DiffOp struct {
+	Kind    DiffKind
+	Ref     string // mot des sous-titres manuels
+	Hyp     string // mot des captions auto
+	StartMs int64  // début du mot (référence si présente)
+}
+
+// ASRComparison : résultat de CompareASR.
+type ASRComparison struct {
+	Quality model.ASRQuality
+	Ops     []DiffOp
+}
+
+// compareToken : mot normalisé et son timestamp.
+type compareToken struct {
+	text  string // forme d'origine, pour le rapport
+	key   string // forme comparée
+	start int64
+}
+
+// compareTokens : mots comparables des phrases (voir flattenWords), ponctuation seule ignorée.
+func compareTokens(phrases []Phrase, lang string) []compareToken {
+	var out []compareToken
+	for _, w := range flattenWords(phrases) {
+		key := bareWord(w.Text)
+		if key == "" || !strings.ContainsFunc(key, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		if !IsSpacelessLang(lang) {
+			out = append(out, compareToken{text: strings.TrimSpace(w.Text), key: key, start: w.StartMs})
+			continue
+		}
+		for _, r := range key {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				out = append(out, compareToken{text: string(r), key: string(r), start: w.StartMs})
+			}
+		}
+	}
+	return out
+}
+
+// CompareASR aligne les captions auto (auto) sur les sous-titres manuels (manual)
+// et calcule le WER. lang : langue des deux pistes.
+func CompareASR(manual, auto []Phrase, lang string) ASRComparison {
+	ref := compareTokens(manual, lang)
+	hyp := compareTokens(auto, lang)
+	q := model.ASRQuality{Lang: lang, RefWords: len(ref), HypWords: len(hyp)}
+
+	var ops []DiffOp
+	i, j := 0, 0
+	for i < len(ref) || j < len(hyp) {
+		iEnd := min(i+compareChunkWords, len(ref))
+		jEnd := len(hyp)
+		last := iEnd == len(ref)
+		if !last {
+			// mots des captions jusqu'à la fin du bloc de référence (+ marge), dans des bornes raisonnables
+			limit := ref[iEnd-1].start + compareSlackMs
+			jEnd = j
+			for jEnd < len(hyp) && hyp[jEnd].start <= limit {
+				jEnd++
+			}
+			jEnd = max(jEnd, min(j+compareChunkWords/2, len(hyp)))
+			jEnd = min(jEnd, j+2*compareChunkWords)
+		}
+		chunk := alignWords(ref[i:iEnd], hyp[j:jEnd])
+		if !(last && jEnd == len(hyp)) {
+			// valider jusqu'à la dernière ancre : la fin du bloc, coupée arbitrairement,
+			// est réalignée avec le bloc suivant
+			if a := lastAnchor(chunk); a >= 0 {
+				chunk = chunk[:a+1]
+			}
+		}
+		for _, op := range chunk {
+			switch op.Kind {
+			case DiffSubstitute:
+				q.Substitutions++
+			case DiffDelete:
+				q.Deletions++
+			case DiffInsert:
+				q.Insertions++
+			}
+			if op.Kind != DiffInsert {
+				i++
+			}
+			if op.Kind != DiffDelete {
+				j++
+			}
+		}
+		ops = append(ops, chunk...)
+	}
+	if q.RefWords > 0 {
+		q.WER = float64(q.Substitutions+q.Deletions+q.Insertions) / float64(q.RefWords)
+	}
+	return ASRComparison{Quality: q, Ops: ops}
+}
+
+// lastAnchor : index du dernier DiffEqual du bloc (-1 si aucun).
+func lastAnchor(ops []DiffOp) int {
+	for k := len(ops) - 1; k >= 0; k-- {
+		if ops[k].Kind == DiffEqual {
+			return k
+		}
+	}
+	return -1
+}
+
+// alignWords : distance de Levenshtein mot à mot et chemin d'édition.
+func alignWords(ref, hyp []compareToken) []DiffOp {
+	n, m := len(ref), len(hyp)
+	// dist[a][b] : coût d'alignement de ref[:a] et hyp[:b]
+	dist := make([][]int32, n+1)
+	for a := range dist {
+		dist[a] = make([]int32, m+1)
+		dist[a][0] = int32(a)
+	}
+	for b := 0; b <= m; b++ {
+		dist[0][b] = int32(b)
+	}
+	for a := 1; a <= n; a++ {
+		for b := 1; b <= m; b++ {
+			sub := dist[a-1][b-1]
+			if ref[a-1].key != hyp[b-1].key {
+				sub++
+			}
+			dist[a][b] = min(sub, dist[a-1][b]+1, dist[a][b-1]+1)
+		}
+	}
+
+	ops := make([]DiffOp, 0, max(n, m))
+	a, b := n, m
+	for a > 0 || b > 0 {
+		switch {
+		case a > 0 && b > 0 && ref[a-1].key == hyp[b-1].key && dist[a][b] == dist[a-1][b-1]:
+			ops = append(ops, DiffOp{Kind: DiffEqual, Ref: ref[a-1].text, Hyp: hyp[b-1].text, StartMs: ref[a-1].start})
+			a, b = a-1, b-1
+		case a > 0 && b > 0 && dist[a][b] == dist[a-1][b-1]+1:
+			ops = append(ops, DiffOp{Kind: DiffSubstitute, Ref: ref[a-1].text, Hyp: hyp[b-1].text, StartMs: ref[a-1].start})
+			a, b = a-1, b-1
+		case a > 0 && dist[a][b] == dist[a-1][b]+1:
+			ops = append(ops, DiffOp{Kind: DiffDelete, Ref: ref[a-1].text, StartMs: ref[a-1].start})
+			a--
+		default:
+			ops = append(ops, DiffOp{Kind: DiffInsert, Hyp: hyp[b-1].text, StartMs: hyp[b-1].start})
+			b--
+		}
+	}
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	return ops
+}
+
+// Markdown : rapport de comparaison (WER, compteurs, verdict) suivi des passages
+// qui diffèrent, au format wdiff : [-manuel-]{+auto+}, avec lien horodaté.
+func (c ASRComparison) Markdown(title, url string) string {
+	q := c.Quality
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Captions auto vs sous-titres manuels : %s\n\n", title)
+	fmt.Fprintf(&b, "- WER : %s (%s)\n", q.WERPercent(), q.Verdict())
+	fmt.Fprintf(&b, "- Mots : %d manuels, %d auto\n", q.RefWords, q.HypWords)
+	fmt.Fprintf(&b, "- Substitutions : %d ; suppressions : %d ; insertions : %d\n", q.Substitutions, q.Deletions, q.Insertions)
+	if r := q.Recommendation(); r != "" {
+		fmt.Fprintf(&b, "- Conseil : %s\n", r)
+	}
+	lines := c.diffLines(url)
+	if len(lines) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n## Différences\n\n`[-sous-titres manuels-]{+captions auto+}`\n\n")
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteString("\n\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// diffLines : une ligne par groupe de différences proches, diffContextWords mots
+// identiques de contexte de part et d'autre.
+func (c ASRComparison) diffLines(url string) []string {
+	var lines []string
+	ops := c.Ops
+	prevEnd := 0 // fin de la ligne précédente : le contexte n'est pas répété
+	for k := 0; k < len(ops); {
+		if ops[k].Kind == DiffEqual {
+			k++
+			continue
+		}
+		start := max(k-diffContextWords, prevEnd)
+		// étendre tant qu'une autre différence suit à moins de 2×contexte mots identiques
+		end, equal := k, 0
+		for end < len(ops) && equal <= 2*diffContextWords {
+			if ops[end].Kind == DiffEqual {
+				equal++
+			} else {
+				equal = 0
+			}
+			end++
+		}
+		end = min(end-equal+diffContextWords, len(ops))
+		var parts []string
+		for _, op := range ops[start:end] {
+			switch op.Kind {
+			case DiffEqual:
+				parts = append(parts, op.Ref)
+			case DiffSubstitute:
+				parts = append(parts, "[-"+op.Ref+"-]{+"+op.Hyp+"+}")
+			case DiffDelete:
+				parts = append(parts, "[-"+op.Ref+"-]")
+			case DiffInsert:
+				parts = append(parts, "{+"+op.Hyp+"+}")
+			}
+		}
+		lines = append(lines, timestampLink(ops[k].StartMs, url)+" "+strings.Join(parts, " "))
+		k, prevEnd = end, end
+	}
+	return lines
+}
@@ -0,0 +1,80 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// timedPhrases : une phrase par texte, mots espacés de 300 ms.
+func timedPhrases(texts ...string) []Phrase {
+	var out []Phrase
+	var ms int64
+	for _, text := range texts {
+		p := Phrase{TimestampMs: ms, Text: text}
+		for _, f := range strings.Fields(text) {
+			p.Words = append(p.Words, Word{Text: f, StartMs: ms, EndMs: ms + 300})
+			ms += 300
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func TestCompareASR(t *testing.T) {
+	tests := []struct {
+		name          string
+		manual, auto  string
+		lang          string
+		s, d, i       int
+		wantReference int
+	}{
+		{"identiques (casse et ponctuation ignorées)", "Hello, world. How are you?", "hello world how are you", "en", 0, 0, 0, 5},
+		{"substitution", "the quick brown fox", "the quick brow fox", "en", 1, 0, 0, 4},
+		{"suppression", "we shipped it today", "we shipped today", "en", 0, 1, 0, 4},
+		{"insertion (remplissage)", "so we shipped it", "so um we shipped it", "en", 0, 0, 1, 4},
+		{"japonais : par caractère", "今日は晴れです。", "今日は雨です", "ja", 1, 1, 0, 7},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := CompareASR(timedPhrases(tc.manual), timedPhrases(tc.auto), tc.lang).Quality
+			if q.Substitutions != tc.s || q.Deletions != tc.d || q.Insertions != tc.i || q.RefWords != tc.wantReference {
+				t.Errorf("S=%d D=%d I=%d N=%d ; want S=%d D=%d I=%d N=%d",
+					q.Substitutions, q.Deletions, q.Insertions, q.RefWords, tc.s, tc.d, tc.i, tc.wantReference)
+			}
+		})
+	}
+}
+
+// Sur un long transcript, les erreurs proches des frontières de blocs sont
+// comptées une seule fois, malgré un décalage de timing entre les pistes.
+func TestCompareASRChunks(t *testing.T) {
+	var manual, auto []string
+	for k := range 2_000 {
+		w := fmt.Sprintf("w%d", k%37)
+		manual = append(manual, w)
+		switch {
+		case k%150 == 0:
+			auto = append(auto, "x") // substitution
+		case k%190 == 0:
+			// suppression
+		default:
+			auto = append(auto, w)
+		}
+	}
+	m := timedPhrases(strings.Join(manual, " "))
+	a := timedPhrases(strings.Join(auto, " "))
+	for k := range a[0].Words {
+		a[0].Words[k].StartMs += 700 // captions légèrement en retard
+	}
+	c := CompareASR(m, a, "en")
+	q := c.Quality
+	// k%150 == 0 : 14 valeurs ; k%190 == 0 hors multiples de 150 : 190, 380... 1900
+	wantS, wantD := 14, 10
+	if q.Substitutions != wantS || q.Deletions != wantD || q.Insertions != 0 {
+		t.Errorf("S=%d D=%d I=%d ; want S=%d D=%d I=0", q.Substitutions, q.Deletions, q.Insertions, wantS, wantD)
+	}
+	if !strings.Contains(c.Markdown("Test", ""), "[-w0-]{+x+}") {
+		t.Errorf("diff sans substitution w0 -> x :\n%s", c.Markdown("Test", ""))
+	}
+}
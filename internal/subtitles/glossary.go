@@ -0,0 +1,308 @@
+package subtitles
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// glossary.go : corrections de l'ASR par remplacements ("cube and eighties" ->
+// "Kubernetes"), chargés depuis les glossaires (voir internal/glossary).
+//   - exact et ignore_case ne remplacent que des mots entiers (sauf en japonais,
+//     chinois... où il n'y a pas d'espace entre les mots) ;
+//   - regex : expression Go, $1... dans le remplacement ;
+//   - les timings par mot sont conservés : les mots remplacés ensemble
+//     fusionnent, un remplacement de plusieurs mots se partage leur durée.
+
+// MatchMode : façon de comparer le texte d'un remplacement.
+type MatchMode string
+
+const (
+	MatchExact      MatchMode = "exact"       // casse comprise
+	MatchIgnoreCase MatchMode = "ignore_case" // sans tenir compte de la casse
+	MatchRegex      MatchMode = "regex"       // expression régulière
+)
+
+// ParseMatchMode convertit la valeur du glossaire en MatchMode ("ignore_case" par défaut).
+func ParseMatchMode(s string) (MatchMode, error) {
+	switch MatchMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", MatchIgnoreCase:
+		return MatchIgnoreCase, nil
+	case MatchExact:
+		return MatchExact, nil
+	case MatchRegex:
+		return MatchRegex, nil
+	default:
+		return "", fmt.Errorf("mode de glossaire inconnu: %s", s)
+	}
+}
+
+// Replacement : une correction du glossaire (voir NewReplacement).
+type Replacement struct {
+	From string
+	To   string
+	Mode MatchMode
+	re   *regexp.Regexp
+}
+
+// NewReplacement compile une correction. En exact et ignore_case, les espaces
+// de From correspondent à n'importe quel blanc.
+func NewReplacement(from, to string, mode MatchMode) (Replacement, error) {
+	r := Replacement{From: from, To: to, Mode: mode}
+	pattern := from
+	if mode != MatchRegex {
+		words := strings.Fields(from)
+		if len(words) == 0 {
+			return r, fmt.Errorf("glossaire: remplacement vide vers %q", to)
+		}
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		pattern = strings.Join(words, `\s+`)
+		if mode == MatchIgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return r, fmt.Errorf("glossaire: regex %q: %w", from, err)
+	}
+	r.re = re
+	return r, nil
+}
+
+// textEdit : remplacement [start, end) du texte d'origine par [newStart, newEnd) du nouveau.
+type textEdit struct {
+	start, end       int
+	newStart, newEnd int
+}
+
+// replace applique r à text ; edits vide si rien n'a changé.
+func (r Replacement) replace(text, lang string) (string, []textEdit) {
+	matches := r.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, nil
+	}
+	wholeWords := r.Mode != MatchRegex && !IsSpacelessLang(lang)
+	var (
+		b     strings.Builder
+		edits []textEdit
+		last  int
+	)
+	for _, m := range matches {
+		if m[0] == m[1] || (wholeWords && !isWholeWord(text, m[0], m[1])) {
+			continue
+		}
+		repl := r.To
+		if r.Mode == MatchRegex {
+			repl = string(r.re.ExpandString(nil, r.To, text, m))
+		}
+		if repl == text[m[0]:m[1]] {
+			continue
+		}
+		b.WriteString(text[last:m[0]])
+		ns := b.Len()
+		b.WriteString(repl)
+		edits = append(edits, textEdit{start: m[0], end: m[1], newStart: ns, newEnd: b.Len()})
+		last = m[1]
+	}
+	if len(edits) == 0 {
+		return text, nil
+	}
+	b.WriteString(text[last:])
+	return b.String(), edits
+}
+
+// isWholeWord : text[start:end] n'est pas collé à une lettre ou un chiffre.
+func isWholeWord(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		first, _ := utf8.DecodeRuneInString(text[start:])
+		if isWordRune(before) && isWordRune(first) {
+			return false
+		}
+	}
+	if end < len(text) {
+		last, _ := utf8.DecodeLastRuneInString(text[:end])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(last) && isWordRune(after) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// GlossaryHit : un remplacement appliqué.
+type GlossaryHit struct {
+	From        string // texte trouvé dans le transcript
+	To          string
+	TimestampMs int64
+}
+
+// GlossaryReport : remplacements appliqués, dans l'ordre du transcript et des règles.
+type GlossaryReport struct {
+	Hits []GlossaryHit
+}
+
+// String : "cube and eighties → Kubernetes ×2 (00:00:12, 00:04:56) ; ..." ou "aucun remplacement".
+func (r GlossaryReport) String() string {
+	type group struct {
+		from, to string
+		times    []string
+	}
+	var groups []*group
+	index := map[[2]string]*group{}
+	for _, h := range r.Hits {
+		key := [2]string{h.From, h.To}
+		g, ok := index[key]
+		if !ok {
+			g = &group{from: h.From, to: h.To}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.times = append(g.times, model.Seconds(h.TimestampMs/1000).TimestampHHMMSS())
+	}
+	if len(groups) == 0 {
+		return "aucun remplacement"
+	}
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = fmt.Sprintf("%s → %s ×%d (%s)", g.from, g.to, len(g.times), strings.Join(g.times, ", "))
+	}
+	return strings.Join(parts, " ; ")
+}
+
+// ApplyGlossary applique les remplacements, dans l'ordre, au texte des phrases
+// (copie). Une phrase vidée par un remplacement est supprimée.
+func ApplyGlossary(phrases []Phrase, reps []Replacement, lang string) ([]Phrase, GlossaryReport) {
+	var rep GlossaryReport
+	if len(reps) == 0 {
+		return phrases, rep
+	}
+	out := make([]Phrase, 0, len(phrases))
+	for _, p := range phrases {
+		for _, r := range reps {
+			p = replaceInPhrase(p, r, lang, &rep)
+		}
+		if strings.TrimSpace(p.Text) == "" {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out, rep
+}
+
+// replaceInPhrase applique r à p et reconstruit Words si ceux-ci reflètent le texte.
+func replaceInPhrase(p Phrase, r Replacement, lang string, rep *GlossaryReport) Phrase {
+	text, edits := r.replace(p.Text, lang)
+	if len(edits) == 0 {
+		return p
+	}
+	timed := len(p.Words) > 0 && wordsMatchText(p)
+	var spans [][2]int
+	if timed {
+		spans = fieldSpans(p.Text)
+	}
+	for _, e := range edits {
+		ts := p.TimestampMs
+		if timed {
+			// premier mot touché par le remplacement
+			for i, s := range spans {
+				if s[1] > e.start {
+					ts = p.Words[i].StartMs
+					break
+				}
+			}
+		}
+		rep.Hits = append(rep.Hits, GlossaryHit{From: p.Text[e.start:e.end], To: text[e.newStart:e.newEnd], TimestampMs: ts})
+	}
+	if timed {
+		p.Words = rebuildWords(p.Words, spans, edits, text)
+		if len(p.Words) > 0 {
+			p.TimestampMs = p.Words[0].StartMs
+		}
+	}
+	p.Text = normalizeWhitespace(text)
+	p.RuneCount = utf8.RuneCountInString(p.Text)
+	p.WordCount = CountWords(p.Text, lang)
+	return p
+}
+
+// fieldSpans : positions [début, fin) des mots (strings.Fields) de s.
+func fieldSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				spans = append(spans, [2]int{start, i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+// rebuildWords : les mots non touchés sont gardés ; ceux touchés par un même
+// remplacement (ou par des remplacements qui se chevauchent via un mot) forment
+// un groupe remplacé par les mots du nouveau texte, sur la durée du groupe.
+func rebuildWords(words []Word, spans [][2]int, edits []textEdit, text string) []Word {
+	// mapOld : position du texte d'origine -> position du nouveau texte (hors remplacement)
+	mapOld := func(x int) int {
+		for k := len(edits) - 1; k >= 0; k-- {
+			if edits[k].end <= x {
+				return x - edits[k].end + edits[k].newEnd
+			}
+		}
+		return x
+	}
+	overlaps := func(s [2]int, e textEdit) bool { return s[0] < e.end && e.start < s[1] }
+
+	out := make([]Word, 0, len(words))
+	for i, k := 0, 0; i < len(words); {
+		for k < len(edits) && edits[k].end <= spans[i][0] {
+			k++
+		}
+		if k == len(edits) || !overlaps(spans[i], edits[k]) {
+			out = append(out, words[i])
+			i++
+			continue
+		}
+		gs, ge := min(spans[i][0], edits[k].start), spans[i][1]
+		first := i
+		for i < len(words) && (spans[i][0] < ge || first == i) {
+			ge = max(ge, spans[i][1])
+			for k < len(edits) && edits[k].start < ge {
+				ge = max(ge, edits[k].end)
+				k++
+			}
+			i++
+		}
+		fields := strings.Fields(text[mapOld(gs):mapOld(ge)])
+		start, end := words[first].StartMs, words[i-1].EndMs
+		for n, f := range fields {
+			w := Word{Text: f, StartMs: start, EndMs: end}
+			if len(fields) > 1 {
+				w.StartMs = start + (end-start)*int64(n)/int64(len(fields))
+				w.EndMs = start + (end-start)*int64(n+1)/int64(len(fields))
+			}
+			out = append(out, w)
+		}
+	}
+	return out
+}
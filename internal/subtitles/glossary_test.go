@@ -0,0 +1,84 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestApplyGlossary(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   string
+		mode       MatchMode
+		lang       string
+		text       string
+		want       string
+		wantReport string
+	}{
+		{"plusieurs mots, sans casse", "cube and eighties", "Kubernetes", MatchIgnoreCase, "en",
+			"We deploy on Cube and  eighties today.", "We deploy on Kubernetes today.",
+			"Cube and  eighties → Kubernetes ×1 (00:00:01)"},
+		{"exact : casse respectée", "go", "Go", MatchExact, "en",
+			"Go go gopher", "Go Go gopher", "go → Go ×1 (00:00:01)"},
+		{"mot entier uniquement", "ama", "AMA", MatchIgnoreCase, "en",
+			"An ama about amazon.", "An AMA about amazon.", "ama → AMA ×1 (00:00:01)"},
+		{"déjà correct : rien à signaler", "kubernetes", "Kubernetes", MatchIgnoreCase, "en",
+			"Kubernetes rocks.", "Kubernetes rocks.", "aucun remplacement"},
+		{"regex avec groupe", `(?i)\bk(?:8|eight)s\b`, "Kubernetes", MatchRegex, "en",
+			"Run k8s and K eight s.", "Run Kubernetes and K eight s.", "k8s → Kubernetes ×1 (00:00:01)"},
+		{"regex : références", `(\d+) ?pour ?cent`, "$1 %", MatchRegex, "fr",
+			"Une hausse de 12 pour cent.", "Une hausse de 12 %.", "12 pour cent → 12 % ×1 (00:00:01)"},
+		{"japonais : pas de mots entiers", "クバネテス", "Kubernetes", MatchIgnoreCase, "ja",
+			"今日はクバネテスの話", "今日はKubernetesの話", "クバネテス → Kubernetes ×1 (00:00:01)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewReplacement(tc.from, tc.to, tc.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			in := []Phrase{{TimestampMs: 1500, Text: tc.text, WordCount: CountWords(tc.text, tc.lang)}}
+			got, rep := ApplyGlossary(in, []Replacement{r}, tc.lang)
+			if got[0].Text != tc.want {
+				t.Errorf("Text = %q; want %q", got[0].Text, tc.want)
+			}
+			if got[0].WordCount != CountWords(tc.want, tc.lang) {
+				t.Errorf("WordCount = %d", got[0].WordCount)
+			}
+			if rep.String() != tc.wantReport {
+				t.Errorf("report = %q; want %q", rep.String(), tc.wantReport)
+			}
+			if in[0].Text != tc.text {
+				t.Errorf("ApplyGlossary a modifié l'original")
+			}
+		})
+	}
+}
+
+func TestApplyGlossaryWordTimings(t *testing.T) {
+	words := []Word{
+		{Text: "on", StartMs: 0, EndMs: 400},
+		{Text: "cube", StartMs: 400, EndMs: 800},
+		{Text: "and", StartMs: 800, EndMs: 1000},
+		{Text: "eighties", StartMs: 1000, EndMs: 1600},
+		{Text: "with", StartMs: 1600, EndMs: 2000},
+		{Text: "k8s", StartMs: 2000, EndMs: 2600},
+	}
+	in := []Phrase{{TimestampMs: 0, Text: "on cube and eighties with k8s", Words: words}}
+	r1, _ := NewReplacement("cube and eighties", "Kubernetes", MatchIgnoreCase)
+	r2, _ := NewReplacement("k8s", "Kubernetes clusters", MatchExact)
+	got, rep := ApplyGlossary(in, []Replacement{r1, r2}, "en")
+
+	var b strings.Builder
+	for _, w := range got[0].Words {
+		fmt.Fprintf(&b, "%s@%d-%d ", w.Text, w.StartMs, w.EndMs)
+	}
+	want := "on@0-400 Kubernetes@400-1600 with@1600-2000 Kubernetes@2000-2300 clusters@2300-2600 "
+	if b.String() != want {
+		t.Errorf("Words = %q; want %q", b.String(), want)
+	}
+	if len(rep.Hits) != 2 || rep.Hits[1].TimestampMs != 2000 {
+		t.Errorf("hits = %+v", rep.Hits)
+	}
+}
@@ -0,0 +1,67 @@
+package subtitles
+
+import (
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// nombre de mots visé pour la citation d'un moment clé
+const keyMomentQuoteWords = 30
+
+// KeyMoments rattache chaque pic du heatmap à la phrase la plus proche
+// (nearestPhraseIndex) et au chapitre qui contient le début de cette phrase :
+// lien, citation et chapitre désignent le même instant.
+// La citation part de cette phrase et s'étend aux suivantes tant qu'elles
+// commencent avant la fin du pic, dans la limite de ~keyMomentQuoteWords mots.
+func (t Transcript) KeyMoments(peaks []model.HeatmapPoint) []model.KeyMoment {
+	if len(peaks) == 0 {
+		return nil
+	}
+	phrases := make([]Phrase, len(t.Phrases))
+	copy(phrases, t.Phrases)
+	ensureSortedPhrases(phrases)
+	chaps := make([]model.Chapter, len(t.Chapters))
+	copy(chaps, t.Chapters)
+	sortChapters(chaps)
+
+	out := make([]model.KeyMoment, 0, len(peaks))
+	for _, pk := range peaks {
+		km := model.KeyMoment{
+			Peak:         pk,
+			TimestampMs:  pk.StartMs,
+			ChapterIndex: -1,
+		}
+
+		if idx, _ := nearestPhraseIndex(phrases, pk.StartMs); idx >= 0 {
+			km.TimestampMs = phrases[idx].TimestampMs
+			km.Quote = quoteFrom(phrases, idx, pk.EndMs)
+		}
+
+		for i, c := range chaps {
+			if c.Start.Milliseconds() > km.TimestampMs {
+				break
+			}
+			km.ChapterIndex = i
+			km.Chapter = c.Title
+		}
+		out = append(out, km)
+	}
+	return out
+}
+
+// quoteFrom concatène phrases[idx] et les suivantes qui commencent avant endMs,
+// sans dépasser keyMomentQuoteWords mots (la première phrase est toujours gardée).
+func quoteFrom(phrases []Phrase, idx int, endMs int64) string {
+	var parts []string
+	words := 0
+	for i := idx; i < len(phrases); i++ {
+		p := phrases[i]
+		if i > idx && (p.TimestampMs >= endMs || words+p.WordCount > keyMomentQuoteWords) {
+			break
+		}
+		parts = append(parts, strings.TrimSpace(p.Text))
+		words += p.WordCount
+	}
+	return strings.Join(parts, " ")
+}
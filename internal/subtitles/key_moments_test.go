@@ -0,0 +1,83 @@
+package subtitles
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestKeyMoments(t *testing.T) {
+	tr := Transcript{
+		Phrases: []Phrase{
+			{TimestampMs: 0, Text: "Bonjour à tous.", WordCount: 3},
+			{TimestampMs: 55_000, Text: "Voici la démo.", WordCount: 3},
+			{TimestampMs: 62_000, Text: "Elle tient en une ligne.", WordCount: 5},
+			{TimestampMs: 70_000, Text: "Passons aux questions.", WordCount: 3},
+		},
+		Chapters: []model.Chapter{{Start: 0, Title: "Intro"}, {Start: 60, Title: "Démo"}},
+	}
+	tests := []struct {
+		name      string
+		peak      model.HeatmapPoint
+		wantTs    int64
+		wantQuote string
+		wantIndex int
+		wantTitle string
+	}{
+		// milieu du pic dans "Démo", mais la phrase citée commence dans "Intro"
+		{"chapitre de la phrase citée", model.HeatmapPoint{StartMs: 56_000, EndMs: 66_000},
+			55_000, "Voici la démo. Elle tient en une ligne.", 0, "Intro"},
+		{"premier chapitre", model.HeatmapPoint{StartMs: 1_000, EndMs: 2_000},
+			0, "Bonjour à tous.", 0, "Intro"},
+		{"citation bornée par la fin du pic", model.HeatmapPoint{StartMs: 61_000, EndMs: 70_000},
+			62_000, "Elle tient en une ligne.", 1, "Démo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kms := tr.KeyMoments([]model.HeatmapPoint{tt.peak})
+			if len(kms) != 1 {
+				t.Fatalf("got %d moments; want 1", len(kms))
+			}
+			km := kms[0]
+			if km.TimestampMs != tt.wantTs || km.Quote != tt.wantQuote || km.ChapterIndex != tt.wantIndex || km.Chapter != tt.wantTitle {
+				t.Errorf("got {%d %q %d %q}; want {%d %q %d %q}", km.TimestampMs, km.Quote, km.ChapterIndex, km.Chapter,
+					tt.wantTs, tt.wantQuote, tt.wantIndex, tt.wantTitle)
+			}
+		})
+	}
+
+	// sans chapitre : -1 ; l'index 0 reste présent dans le JSON
+	none := Transcript{Phrases: tr.Phrases}.KeyMoments([]model.HeatmapPoint{{StartMs: 1_000, EndMs: 2_000}})
+	if none[0].ChapterIndex != -1 {
+		t.Errorf("ChapterIndex sans chapitre = %d; want -1", none[0].ChapterIndex)
+	}
+	b, _ := json.Marshal(tr.KeyMoments([]model.HeatmapPoint{{StartMs: 1_000, EndMs: 2_000}})[0])
+	if !strings.Contains(string(b), `"chapter_index":0`) {
+		t.Errorf("chapter_index 0 absent du JSON : %s", b)
+	}
+}
+
+func TestQuoteFrom(t *testing.T) {
+	phrases := []Phrase{
+		{TimestampMs: 0, Text: "un deux trois", WordCount: 3},
+		{TimestampMs: 1_000, Text: strings.Repeat("mot ", keyMomentQuoteWords), WordCount: keyMomentQuoteWords},
+		{TimestampMs: 2_000, Text: "fin", WordCount: 1},
+	}
+	tests := []struct {
+		idx   int
+		endMs int64
+		want  string
+	}{
+		{0, 500, "un deux trois"},   // la phrase suivante commence après le pic
+		{0, 5_000, "un deux trois"}, // limite de mots dépassée
+		{1, 5_000, strings.TrimSpace(strings.Repeat("mot ", keyMomentQuoteWords))}, // la première phrase est toujours gardée
+		{2, 0, "fin"},
+	}
+	for _, tt := range tests {
+		if got := quoteFrom(phrases, tt.idx, tt.endMs); got != tt.want {
+			t.Errorf("quoteFrom(%d, %d) = %q; want %q", tt.idx, tt.endMs, got, tt.want)
+		}
+	}
+}
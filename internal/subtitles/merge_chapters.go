@@ -1,9 +1,13 @@
 package subtitles
 
 import (
+	"cmp"
+	"fmt"
+	"math"
 	"slices"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/patrickprogramme/subscribe/pkg/model"
 )
@@ -17,6 +21,9 @@ type event struct {
 	isChapter bool   // Indique s'il s'agit d'un chapitre (true) ou d'une phrase (false)
 	text      string // Contenu textuel (titre du chapitre ou phrase)
 	order     int    // Critère de tri stable en cas d'égalité de ts
+	endMs     int64  // fin de la phrase (0 si inconnue), pour les pauses du layout asParagraphs
+	words     int    // nombre de mots de la phrase
+	speaker   string // locuteur de la phrase ("" si inconnu)
 }
 
 // absInt64 retourne la valeur absolue d'un entier 64 bits.
@@ -114,26 +121,70 @@ func nearestPhraseIndex(phrases []Phrase, chTs int64) (int, int64) {
 	return nearest, minDist
 }
 
-// adjustMiddleChapters : pour chaque chapter dans middle, trouve nearest phrase et éventuellement "nudge"
-// si dist <= thresholdMs (threshold==0 => toujours nudge).
+// ChapterSnapMode : recalage d'un chapitre sur le début d'une phrase.
+type ChapterSnapMode string
+
+const (
+	SnapNearest      ChapterSnapMode = "nearest"       // phrase la plus proche, avant ou après
+	SnapNextSentence ChapterSnapMode = "next_sentence" // première phrase qui ouvre une phrase grammaticale
+	SnapNone         ChapterSnapMode = "none"          // timestamp du chapitre tel quel
+)
+
+// ParseChapterSnapMode convertit la valeur de config en ChapterSnapMode ("nearest" par défaut).
+func ParseChapterSnapMode(s string) (ChapterSnapMode, error) {
+	switch s {
+	case "", "nearest":
+		return SnapNearest, nil
+	case "next_sentence":
+		return SnapNextSentence, nil
+	case "none":
+		return SnapNone, nil
+	default:
+		return "", fmt.Errorf("recalage des chapitres inconnu: %s", s)
+	}
+}
+
+// ChapterSnap : recalage des chapitres dans les rendus et ChapterSlice.
+// La valeur zéro recale toujours sur la phrase la plus proche.
+type ChapterSnap struct {
+	Mode  ChapterSnapMode // "" = SnapNearest
+	MaxMs int64           // distance maximale du recalage (0 = sans limite)
+}
+
+// snapChapterMs : position du chapitre chMs sur la ligne de temps, juste avant
+// la phrase retenue (les chapitres passent avant les phrases à ts égal).
+// Sans phrase à moins de MaxMs, le chapitre garde son timestamp. En mode
+// next_sentence, sans début de phrase à portée (captions sans ponctuation),
+// on se rabat sur la phrase la plus proche.
+func snapChapterMs(phrases []Phrase, chMs int64, snap ChapterSnap) int64 {
+	within := func(d int64) bool { return snap.MaxMs == 0 || d <= snap.MaxMs }
+	nudge := func(target int64) int64 { return max(target-1, 0) }
+	switch snap.Mode {
+	case SnapNone:
+		return chMs
+	case SnapNextSentence:
+		i, _ := slices.BinarySearchFunc(phrases, chMs, func(p Phrase, key int64) int {
+			return cmp.Compare(p.TimestampMs, key)
+		})
+		for j := i; j < len(phrases) && within(phrases[j].TimestampMs-chMs); j++ {
+			if j == 0 || endsSentence(phrases[j-1].Text) {
+				return nudge(phrases[j].TimestampMs)
+			}
+		}
+	}
+	if idx, dist := nearestPhraseIndex(phrases, chMs); idx >= 0 && within(dist) {
+		return nudge(phrases[idx].TimestampMs)
+	}
+	return chMs
+}
+
+// adjustMiddleChapters : recale chaque chapitre de middle (voir snapChapterMs).
 // Retourne une slice de "events" ready-to-merge (chapters with adjusted timestamps).
-func adjustMiddleChapters(middle []model.Chapter, phrases []Phrase, thresholdMs int64, baseOrder int) []event {
+func adjustMiddleChapters(middle []model.Chapter, phrases []Phrase, snap ChapterSnap, baseOrder int) []event {
 	events := make([]event, 0, len(middle))
 	for i, ch := range middle {
-		chMs := ch.Start.Milliseconds()
-		idx, dist := nearestPhraseIndex(phrases, chMs)
-		adjusted := chMs
-		if idx >= 0 && (thresholdMs == 0 || dist <= thresholdMs) {
-			// nudge juste avant la phrase la plus proche
-			target := phrases[idx].TimestampMs
-			if target > 0 {
-				adjusted = target - 1
-			} else {
-				adjusted = 0
-			}
-		}
 		events = append(events, event{
-			ts:        adjusted,
+			ts:        snapChapterMs(phrases, ch.Start.Milliseconds(), snap),
 			isChapter: true,
 			text:      ch.Title,
 			order:     baseOrder + i,
@@ -149,24 +200,38 @@ func buildEventsFromPhrases(phrases []Phrase, baseOrder int) []event {
 		ev = append(ev, event{
 			ts:        p.TimestampMs,
 			isChapter: false,
-			text:      p.Text,
+			text:      p.displayText(),
 			order:     baseOrder + i,
+			endMs:     p.EndMs,
+			words:     phraseWords(p),
+			speaker:   p.Speaker,
 		})
 	}
 	return ev
 }
 
+// phraseWords : WordCount si renseigné, sinon compté sur le texte : par espaces
+// s'il en contient (comme le reste du pipeline), par écriture sinon (japonais...).
+func phraseWords(p Phrase) int {
+	if p.WordCount > 0 {
+		return p.WordCount
+	}
+	if strings.ContainsFunc(p.Text, unicode.IsSpace) {
+		return len(strings.Fields(p.Text))
+	}
+	return CountWords(p.Text, "")
+}
+
 type OutputTextLayout int
 
 const (
 	asPlain OutputTextLayout = iota
 	asCollapsed
+	asParagraphs // paragraphes (ParagraphOpts, sinon DefaultParagraphOptions), voir Transcript.Paragraphs
 )
 
-// mergeAndRender : tri des events et rendu final en string.
-// request : asCollapsed -> version "collapsed by chapter", asPlain -> classique
-func mergeAndRender(ev []event, request OutputTextLayout) string {
-	// tri stable
+// sortEvents : tri stable par ts, chapitre avant phrase à ts égal, puis order.
+func sortEvents(ev []event) {
 	sort.SliceStable(ev, func(i, j int) bool {
 		if ev[i].ts != ev[j].ts {
 			return ev[i].ts < ev[j].ts
@@ -176,6 +241,16 @@ func mergeAndRender(ev []event, request OutputTextLayout) string {
 		}
 		return ev[i].order < ev[j].order
 	})
+}
+
+// mergeAndRender : tri des events et rendu final en string.
+// request : asCollapsed -> version "collapsed by chapter", asPlain -> classique,
+// asParagraphs -> paragraphes découpés selon popts
+func mergeAndRender(ev []event, request OutputTextLayout, popts ParagraphOptions) string {
+	if request == asParagraphs {
+		return renderParagraphs(ev, popts)
+	}
+	sortEvents(ev)
 
 	var b strings.Builder
 	inContent := false // true si on écrit une ligne de contenu
@@ -234,10 +309,29 @@ func mergeAndRender(ev []event, request OutputTextLayout) string {
 	return out
 }
 
-// transcriptWithChaptersSplit : implémentation méthode plain + chapitres insérés
-// thresholdMs: seuil de distance en millisecondes
-// thresholdMs == 0 => on colle toujours un chapitre sur la phrase la plus proche.
-func (t Transcript) transcriptWithChaptersSplit(thresholdMs int64, request OutputTextLayout) string {
+// transcriptWithChaptersSplit : implémentation méthode plain + chapitres insérés,
+// recalés selon snap (ChapterSnap{} : toujours sur la phrase la plus proche).
+func (t Transcript) transcriptWithChaptersSplit(snap ChapterSnap, request OutputTextLayout) string {
+	if len(t.Phrases) == 0 {
+		// simple : renvoyer chapitres (formatés)
+		var b strings.Builder
+		for _, c := range t.Chapters {
+			b.WriteString(c.Title)
+		}
+		return b.String()
+	}
+	// merge, sort and render
+	popts := DefaultParagraphOptions()
+	if t.ParagraphOpts != nil {
+		popts = *t.ParagraphOpts
+	}
+	return mergeAndRender(t.transcriptEvents(snap), request, popts)
+}
+
+// transcriptEvents construit la ligne de temps triée phrases + chapitres
+// (chapitres "middle" recalés selon snap, voir adjustMiddleChapters).
+// Partagée par les rendus texte (mergeAndRender) et Markdown.
+func (t Transcript) transcriptEvents(snap ChapterSnap) []event {
 	// copies
 	phrases := make([]Phrase, len(t.Phrases))
 	copy(phrases, t.Phrases)
@@ -248,12 +342,11 @@ func (t Transcript) transcriptWithChaptersSplit(thresholdMs int64, request Outpu
 	sortChapters(chaps)
 
 	if len(phrases) == 0 {
-		// simple : renvoyer chapitres (formatés)
-		var b strings.Builder
-		for _, c := range chaps {
-			b.WriteString(c.Title)
+		events := make([]event, 0, len(chaps))
+		for i, c := range chaps {
+			events = append(events, event{ts: c.Start.Milliseconds(), isChapter: true, text: c.Title, order: i})
 		}
-		return b.String()
+		return events
 	}
 
 	firstTs := phrases[0].TimestampMs
@@ -280,7 +373,7 @@ func (t Transcript) transcriptWithChaptersSplit(thresholdMs int64, request Outpu
 
 	// ajuste les chapitres "middle" (leur baseOrder vient après les phrases)
 	baseOrderMiddle := baseOrderPhrases + len(phrases)
-	adjusted := adjustMiddleChapters(middle, phrases, thresholdMs, baseOrderMiddle)
+	adjusted := adjustMiddleChapters(middle, phrases, snap, baseOrderMiddle)
 	events = append(events, adjusted...)
 
 	// chaps_after : conservés tels quels, ordonnés après tous les autres events
@@ -294,6 +387,47 @@ func (t Transcript) transcriptWithChaptersSplit(thresholdMs int64, request Outpu
 		})
 	}
 
-	// merge, sort and render
-	return mergeAndRender(events, request)
+	sortEvents(events)
+	return events
+}
+
+// ChapterSlice retourne les phrases du chapitre i (chapitres triés par début),
+// découpées comme dans les rendus : du chapitre recalé (ChapterSnap) au suivant.
+// Aucune phrase n'est perdue : celles d'un trou entre la fin (End) d'un chapitre
+// et le début du suivant restent au chapitre précédent, celles d'avant le premier
+// chapitre vont au premier. nil si i est hors limites.
+func (t Transcript) ChapterSlice(i int) []Phrase {
+	if i < 0 || i >= len(t.Chapters) || len(t.Phrases) == 0 {
+		return nil
+	}
+	phrases := t.sortedPhrases()
+	chaps := slices.Clone(t.Chapters)
+	sortChapters(chaps)
+
+	firstTs, lastTs := phrases[0].TimestampMs, phrases[len(phrases)-1].TimestampMs
+	startMs := func(c model.Chapter) int64 {
+		ts := c.Start.Milliseconds()
+		if ts <= firstTs || ts > lastTs {
+			return ts // before / after : non recalés (voir splitChapters)
+		}
+		return snapChapterMs(phrases, ts, t.ChapterSnap)
+	}
+	from, to := int64(math.MinInt64), int64(math.MaxInt64)
+	if i > 0 {
+		from = startMs(chaps[i])
+	}
+	if i+1 < len(chaps) {
+		to = startMs(chaps[i+1])
+	}
+	lo, _ := slices.BinarySearchFunc(phrases, from, func(p Phrase, key int64) int {
+		return cmp.Compare(p.TimestampMs, key)
+	})
+	hi := lo
+	for hi < len(phrases) && phrases[hi].TimestampMs < to {
+		hi++
+	}
+	if hi == lo {
+		return nil
+	}
+	return phrases[lo:hi:hi]
 }
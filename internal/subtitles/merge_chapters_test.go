@@ -1,6 +1,7 @@
 package subtitles
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -26,7 +27,7 @@ func TestNearestTieChoosesNextPhrase(t *testing.T) {
 		},
 	}
 
-	out := tr.transcriptWithChaptersSplit(0, asPlain) // threshold 0 => toujours nudge
+	out := tr.transcriptWithChaptersSplit(ChapterSnap{}, asPlain) // sans limite => toujours nudge
 
 	// comportement actuel : midpoint -> voisin de droite (phrase2), le chapitre est attaché à phrase2
 	if !appearsBefore(out, "## Chap", "phrase2") {
@@ -46,7 +47,7 @@ func TestThresholdPreventsNudge(t *testing.T) {
 		},
 	}
 	// threshold = 20000 ms (20s) -> dist (49000) > 20000 -> pas de nudge
-	out := tr.transcriptWithChaptersSplit(20000, asPlain)
+	out := tr.transcriptWithChaptersSplit(ChapterSnap{MaxMs: 20000}, asPlain)
 
 	// on veut la séquence A C B (chapitre entre les deux)
 	if !appearsBefore(out, "A", "C") || !appearsBefore(out, "C", "B") {
@@ -65,7 +66,7 @@ func TestCollapsedModeProducesChapterThenCollapsedContent(t *testing.T) {
 		},
 	}
 
-	out := tr.transcriptWithChaptersSplit(0, asCollapsed)
+	out := tr.transcriptWithChaptersSplit(ChapterSnap{}, asCollapsed)
 
 	if !strings.Contains(out, "## ChapB") {
 		t.Fatalf("expected chapter header present, got:\n%s", out)
@@ -89,7 +90,7 @@ func TestMultipleChaptersStableOrder(t *testing.T) {
 		},
 	}
 
-	out := tr.transcriptWithChaptersSplit(0, asPlain)
+	out := tr.transcriptWithChaptersSplit(ChapterSnap{}, asPlain)
 
 	// on s'attend à conserver l'ordre relatif des chapitres (C1 avant C2)
 	if !appearsBefore(out, "## C1", "## C2") {
@@ -103,9 +104,89 @@ func TestNoPhrasesWithChaptersReturnsConcatenatedTitles(t *testing.T) {
 		Chapters: []model.Chapter{{Start: model.Seconds(0), Title: "OnlyChap1"}, {Start: model.Seconds(10), Title: "OnlyChap2"}},
 	}
 
-	out := tr.transcriptWithChaptersSplit(0, asPlain)
+	out := tr.transcriptWithChaptersSplit(ChapterSnap{}, asPlain)
 	// d'après ton code actuel, si len(phrases)==0 on renvoie la concaténation des Title tels quels
 	if !strings.Contains(out, "OnlyChap1OnlyChap2") {
 		t.Fatalf("expected concatenated chapter titles (raw), got:\n%s", out)
 	}
 }
+
+func TestChapterSnapModes(t *testing.T) {
+	phrases := []Phrase{
+		{TimestampMs: 0, Text: "Bonjour à tous."},
+		{TimestampMs: 58000, Text: "On passe à la suite,"},
+		{TimestampMs: 64000, Text: "enfin presque."},
+		{TimestampMs: 70000, Text: "Voici le montage."},
+	}
+	// chapitre à 1:02 : le plus proche est "enfin presque", la phrase suivante
+	// "Voici le montage" (la précédente finit par un point)
+	tests := []struct {
+		snap ChapterSnap
+		want string
+	}{
+		{ChapterSnap{}, "On passe à la suite,\n\n## C\n\nenfin presque."},
+		{ChapterSnap{Mode: SnapNextSentence}, "enfin presque.\n\n## C\n\nVoici le montage."},
+		{ChapterSnap{Mode: SnapNextSentence, MaxMs: 5000}, "On passe à la suite,\n\n## C\n\nenfin presque."},
+		{ChapterSnap{Mode: SnapNone}, "On passe à la suite,\n\n## C\n\nenfin presque."},
+		{ChapterSnap{Mode: SnapNearest, MaxMs: 1000}, "On passe à la suite,\n\n## C\n\nenfin presque."},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.snap.Mode), func(t *testing.T) {
+			tr := Transcript{Phrases: phrases, Chapters: []model.Chapter{{Start: 62, Title: "C"}}, ChapterSnap: tc.snap}
+			if out := tr.Plain(); !strings.Contains(out, tc.want) {
+				t.Errorf("Plain =\n%s\nwant contains %q", out, tc.want)
+			}
+		})
+	}
+}
+
+func TestChapterSlice(t *testing.T) {
+	tr := Transcript{
+		Phrases: []Phrase{
+			{TimestampMs: 1000, Text: "intro"},
+			{TimestampMs: 29000, Text: "début du setup"},
+			{TimestampMs: 40000, Text: "fin du setup"},
+			{TimestampMs: 95000, Text: "hors chapitre"},
+			{TimestampMs: 120000, Text: "démo"},
+		},
+		Chapters: []model.Chapter{
+			{Start: 2, Title: "Intro"},
+			{Start: 30, End: 90, Title: "Setup"},
+			{Start: 120, Title: "Démo"},
+		},
+	}
+	tests := []struct {
+		i    int
+		want []string
+	}{
+		{0, []string{"intro"}}, // avant le premier chapitre : rattachée au premier
+		{1, []string{"début du setup", "fin du setup", "hors chapitre"}}, // recalé sur 29 s ; le trou 90-120 s reste au Setup
+		{2, []string{"démo"}},
+		{3, nil},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, p := range tr.ChapterSlice(tc.i) {
+			got = append(got, p.Text)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("ChapterSlice(%d) = %q; want %q", tc.i, got, tc.want)
+		}
+	}
+}
+
+func TestPhraseWords(t *testing.T) {
+	tests := []struct {
+		p    Phrase
+		want int
+	}{
+		{Phrase{Text: "the end.Next one"}, 3}, // texte à espaces : strings.Fields
+		{Phrase{Text: "今日は雨です"}, 6},           // sans espaces : compte par écriture
+		{Phrase{Text: "a b", WordCount: 5}, 5},
+	}
+	for _, tc := range tests {
+		if got := phraseWords(tc.p); got != tc.want {
+			t.Errorf("phraseWords(%q) = %d; want %d", tc.p.Text, got, tc.want)
+		}
+	}
+}
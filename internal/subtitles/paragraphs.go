@@ -0,0 +1,166 @@
+package subtitles
+
+import (
+	"strings"
+	"unicode"
+)
+
+// paragraphs.go : regroupement des phrases en paragraphes (layout asParagraphs).
+// Plain() écrit une phrase par ligne, Collapsed() une ligne par chapitre : ici on
+// coupe sur les pauses, une longueur cible en mots et les marqueurs de discours
+// ("so", "now", "alors"...), les chapitres et les changements de locuteur restant
+// des frontières dures.
+
+// ParagraphOptions : seuils du découpage en paragraphes. Une valeur <= 0 désactive la règle.
+type ParagraphOptions struct {
+	PauseMs     int64    // silence entre deux phrases qui force un nouveau paragraphe
+	TargetWords int      // longueur visée : au-delà, coupe sur une demi-pause ou un marqueur
+	MaxWords    int      // longueur maximale : coupe forcée
+	MaxPhrases  int      // nombre maximal de phrases par paragraphe
+	Markers     []string // marqueurs de discours ouvrant un paragraphe (minuscules)
+}
+
+// DefaultParagraphOptions : réglages par défaut (repris par la config).
+func DefaultParagraphOptions() ParagraphOptions {
+	return ParagraphOptions{
+		PauseMs:     2_000,
+		TargetWords: 120,
+		MaxWords:    220,
+		Markers:     DefaultDiscourseMarkers(),
+	}
+}
+
+// DefaultDiscourseMarkers : marqueurs de transition courants en anglais et en français.
+func DefaultDiscourseMarkers() []string {
+	return []string{
+		"so", "now", "okay", "anyway", "next", "finally",
+		"alors", "donc", "maintenant", "ensuite", "enfin", "bref",
+	}
+}
+
+// markdownParagraphs : découpage historique du rendu Markdown quand
+// Transcript.ParagraphOpts est nil (au plus 5 phrases, coupe sur 4 s de pause).
+var markdownParagraphs = ParagraphOptions{PauseMs: 4_000, MaxPhrases: 5}
+
+// textBlock : un titre de chapitre ou un paragraphe (phrases jointes par un espace).
+type textBlock struct {
+	isChapter bool
+	ts        int64 // début du paragraphe (ou du chapitre)
+	text      string
+	speaker   string // locuteur du paragraphe ("" si inconnu)
+}
+
+// speakerPrefix : "Nom : " devant une réplique, "" sans locuteur.
+func (b textBlock) speakerPrefix() string {
+	if b.speaker == "" {
+		return ""
+	}
+	return b.speaker + " : "
+}
+
+// paragraphBlocks parcourt la ligne de temps (transcriptEvents) et regroupe les phrases
+// en paragraphes. Avant d'ajouter une phrase à un paragraphe non vide, on coupe si :
+//   - le locuteur change ;
+//   - le silence depuis la fin de la phrase précédente atteint PauseMs ;
+//   - MaxPhrases ou MaxWords serait dépassé ;
+//   - la phrase commence par un marqueur et le paragraphe a la moitié de TargetWords ;
+//   - le paragraphe a atteint TargetWords et le silence vaut au moins PauseMs/2
+//     (sans PauseMs, TargetWords ne coupe que sur un marqueur).
+func paragraphBlocks(ev []event, opts ParagraphOptions) []textBlock {
+	var blocks []textBlock
+	var para []string
+	var startTs, prevEnd int64
+	var speaker string
+	words := 0
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, textBlock{ts: startTs, text: strings.Join(para, " "), speaker: speaker})
+		}
+		para = nil
+		words = 0
+	}
+
+	for _, e := range ev {
+		if e.isChapter {
+			flush()
+			title := strings.TrimSpace(strings.TrimLeft(e.text, "# "))
+			blocks = append(blocks, textBlock{isChapter: true, ts: e.ts, text: title})
+			continue
+		}
+		text := strings.TrimSpace(e.text)
+		if text == "" {
+			continue
+		}
+		if len(para) > 0 && (e.speaker != speaker || opts.breaksBefore(text, e.ts-prevEnd, len(para), words, e.words)) {
+			flush()
+		}
+		if len(para) == 0 {
+			startTs = e.ts
+			speaker = e.speaker
+		}
+		para = append(para, text)
+		words += e.words
+		prevEnd = max(e.endMs, e.ts)
+	}
+	flush()
+	return blocks
+}
+
+// breaksBefore : règles de coupure avant une phrase (voir paragraphBlocks).
+func (o ParagraphOptions) breaksBefore(text string, gapMs int64, phrases, words, phraseWords int) bool {
+	switch {
+	case o.PauseMs > 0 && gapMs >= o.PauseMs:
+		return true
+	case o.MaxPhrases > 0 && phrases >= o.MaxPhrases:
+		return true
+	case o.MaxWords > 0 && words+phraseWords > o.MaxWords:
+		return true
+	case o.TargetWords > 0 && words*2 >= o.TargetWords && startsWithMarker(text, o.Markers):
+		return true
+	case o.PauseMs > 0 && o.TargetWords > 0 && words >= o.TargetWords && gapMs*2 >= o.PauseMs:
+		return true
+	}
+	return false
+}
+
+// startsWithMarker : le texte commence par l'un des marqueurs, suivi d'un non-lettre
+// ("So, ..." et "Alors on..." oui ; "Sometimes" non).
+func startsWithMarker(text string, markers []string) bool {
+	lower := strings.ToLower(strings.TrimLeft(text, "[(«\"' "))
+	for _, m := range markers {
+		rest, ok := strings.CutPrefix(lower, m)
+		if !ok || m == "" {
+			continue
+		}
+		if rest == "" {
+			return true
+		}
+		if r := []rune(rest)[0]; !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderParagraphs : rendu texte du layout asParagraphs, blocs séparés par une ligne vide.
+func renderParagraphs(ev []event, opts ParagraphOptions) string {
+	sortEvents(ev)
+	blocks := paragraphBlocks(ev, opts)
+	parts := make([]string, 0, len(blocks))
+	for _, bl := range blocks {
+		if bl.isChapter {
+			parts = append(parts, "## "+bl.text)
+			continue
+		}
+		parts = append(parts, bl.speakerPrefix()+bl.text)
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// Paragraphs retourne le transcript découpé en paragraphes, chapitres insérés en titres.
+func (t Transcript) Paragraphs(opts ParagraphOptions) string {
+	if len(t.Phrases) == 0 {
+		return ""
+	}
+	return renderParagraphs(t.transcriptEvents(t.ChapterSnap), opts)
+}
@@ -0,0 +1,98 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestParagraphs(t *testing.T) {
+	opts := ParagraphOptions{PauseMs: 2000, TargetWords: 6, MaxWords: 10, Markers: []string{"so", "alors"}}
+	tests := []struct {
+		name    string
+		phrases []Phrase
+		want    string
+	}{
+		{
+			name: "pause",
+			phrases: []Phrase{
+				{TimestampMs: 0, EndMs: 1000, Text: "One two."},
+				{TimestampMs: 1200, EndMs: 2000, Text: "Three four."},
+				{TimestampMs: 5000, EndMs: 6000, Text: "After silence."},
+			},
+			want: "One two. Three four.\n\nAfter silence.\n",
+		},
+		{
+			name: "marqueur après la moitié de la cible",
+			phrases: []Phrase{
+				{TimestampMs: 0, Text: "One two three."},
+				{TimestampMs: 1000, Text: "So we continue."},
+				{TimestampMs: 2000, Text: "Sometimes not."},
+			},
+			want: "One two three.\n\nSo we continue. Sometimes not.\n",
+		},
+		{
+			name: "longueur maximale",
+			phrases: []Phrase{
+				{TimestampMs: 0, Text: "a b c d e f"},
+				{TimestampMs: 500, Text: "g h i j k"},
+			},
+			want: "a b c d e f\n\ng h i j k\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Transcript{Phrases: tt.phrases}.Paragraphs(opts)
+			if got != tt.want {
+				t.Errorf("got:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParagraphsNoPause(t *testing.T) {
+	// PauseMs 0 désactive les coupures sur pause, y compris la demi-pause après TargetWords
+	opts := ParagraphOptions{TargetWords: 4, MaxWords: 20}
+	tr := Transcript{Phrases: []Phrase{
+		{TimestampMs: 0, EndMs: 1000, Text: "a b c d."},
+		{TimestampMs: 1000, EndMs: 2000, Text: "e f."},
+		{TimestampMs: 9000, EndMs: 9500, Text: "g h."},
+	}}
+	if got, want := tr.Paragraphs(opts), "a b c d. e f. g h.\n"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestParagraphsChapterBoundary(t *testing.T) {
+	opts := DefaultParagraphOptions()
+	tr := Transcript{
+		Phrases: []Phrase{
+			{TimestampMs: 0, Text: "Intro."},
+			{TimestampMs: 1000, Text: "Still intro."},
+			{TimestampMs: 1500, Text: "Main part."},
+		},
+		Chapters: []model.Chapter{{Start: 0, Title: "Début"}, {Start: 1, Title: "Suite"}},
+	}
+	got := tr.transcriptWithChaptersSplit(ChapterSnap{}, asParagraphs)
+	want := "## Début\n\nIntro.\n\n## Suite\n\nStill intro. Main part.\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// asParagraphs suit ParagraphOpts quand il est renseigné
+	one := ParagraphOptions{MaxPhrases: 1}
+	tr.ParagraphOpts = &one
+	got = tr.transcriptWithChaptersSplit(ChapterSnap{}, asParagraphs)
+	want = "## Début\n\nIntro.\n\n## Suite\n\nStill intro.\n\nMain part.\n"
+	if got != want {
+		t.Errorf("ParagraphOpts ignoré, got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// le rendu txt suit ParagraphOpts
+	tr.ParagraphOpts = &opts
+	txt, err := tr.Render(model.FormatTXT)
+	if err != nil || !strings.Contains(string(txt), "Still intro. Main part.") {
+		t.Errorf("Render txt: err=%v\n%s", err, txt)
+	}
+}
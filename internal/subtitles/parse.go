@@ -5,32 +5,119 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
 )
 
-// ParseJSON3Bytes parse un blob JSON ([]byte) et retourne la structure rawJSON3.
-//
-// Utilise json.Decoder en lecture depuis un bytes.Reader quand les données sont
-// déjà en présentes 100% en mémoire : adapté aux fichiers pas trop volumineux
+// ParseBytes parse b selon son format et retourne l'intermédiaire commun rawJSON3,
+// consommé par TransformManualRawToPhrases et TransformAutoRawToPhrases.
+func ParseBytes(b []byte, format model.Format) (rawJSON3, error) {
+	switch format {
+	case model.FormatJSON3:
+		return ParseJSON3Bytes(b)
+	case model.FormatSRV3:
+		return ParseSRV3Bytes(b)
+	case model.FormatVTT:
+		return ParseVTTBytes(b)
+	case model.FormatTTML:
+		return ParseTTMLBytes(b)
+	case model.FormatSRT:
+		return ParseSRTBytes(b)
+	default:
+		return rawJSON3{}, fmt.Errorf("ParseBytes: format de sous-titres non supporté: %q", format)
+	}
+}
+
+// ParseJSON3Bytes parse un blob JSON ([]byte) et retourne la structure rawJSON3
+// (décodage event par event, voir ParseJSON3Reader).
 func ParseJSON3Bytes(b []byte) (rawJSON3, error) {
-	var raw rawJSON3
 	if len(b) == 0 {
-		return raw, fmt.Errorf("ParseJSON3Bytes: empty input")
-	}
-	dec := json.NewDecoder(bytes.NewReader(b))
-	// Ne pas appeler DisallowUnknownFields() car le JSON contient souvent des champs
-	// inutiles/non mappés — on veut ignorer proprement ces champs.
-	if err := dec.Decode(&raw); err != nil {
-		return raw, fmt.Errorf("ParseJSON3Bytes: decode error: %w", err)
+		return rawJSON3{}, fmt.Errorf("ParseJSON3Bytes: empty input")
 	}
-	return raw, nil
+	return ParseJSON3Reader(bytes.NewReader(b))
 }
 
-// ParseJSON3Reader parse depuis un io.Reader (utile si on veut décoder depuis un flux)
+// ParseJSON3Reader parse depuis un io.Reader en décodant les events un par un :
+// le document n'est jamais chargé d'un bloc (seuls les events décodés sont gardés),
+// ce qui tient pour les lives de plusieurs heures.
 func ParseJSON3Reader(r io.Reader) (rawJSON3, error) {
 	var raw rawJSON3
-	dec := json.NewDecoder(r)
-	if err := dec.Decode(&raw); err != nil {
+	err := decodeJSON3(r, &raw.WireMagic, func(ev rawEvent) error {
+		raw.Events = append(raw.Events, ev)
+		return nil
+	})
+	if err != nil {
 		return raw, fmt.Errorf("ParseJSON3Reader: decode error: %w", err)
 	}
 	return raw, nil
 }
+
+// decodeJSON3 parcourt l'objet json3 clé par clé et appelle fn pour chaque
+// élément de "events" dès qu'il est décodé. Les autres clés (pens, wsWinStyles...)
+// sont ignorées.
+func decodeJSON3(r io.Reader, wireMagic *string, fn func(rawEvent) error) error {
+	// Ne pas appeler DisallowUnknownFields() car le JSON contient souvent des champs
+	// inutiles/non mappés — on veut ignorer proprement ces champs.
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key, _ := tok.(string); key {
+		case "wireMagic":
+			if err := dec.Decode(wireMagic); err != nil {
+				return err
+			}
+		case "events":
+			if err := decodeEvents(dec, fn); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeEvents décode le tableau "events" (null accepté) élément par élément.
+func decodeEvents(dec *json.Decoder, fn func(rawEvent) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("events : tableau attendu, obtenu %v", tok)
+	}
+	for dec.More() {
+		var ev rawEvent
+		if err := dec.Decode(&ev); err != nil {
+			return err
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim lit le prochain token et vérifie que c'est le délimiteur want.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("%q attendu, obtenu %v", want, tok)
+	}
+	return nil
+}
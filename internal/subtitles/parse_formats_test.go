@@ -0,0 +1,125 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestParseBytesFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format model.Format
+		input  string
+		want   []Phrase // TimestampMs + Text via TransformManualRawToPhrases
+	}{
+		{
+			name:   "srt",
+			format: model.FormatSRT,
+			input: "\ufeff1\r\n00:00:01,000 --> 00:00:03,500\r\nHello <i>world</i>.\r\n\r\n" +
+				"2\r\n00:00:04,000 --> 00:00:06,000\r\nSecond &amp; last\r\nline.\r\n",
+			want: []Phrase{{TimestampMs: 1000, Text: "Hello world."}, {TimestampMs: 4000, Text: "Second & last line."}},
+		},
+		{
+			name:   "vtt",
+			format: model.FormatVTT,
+			input: "WEBVTT\nKind: captions\n\nNOTE commentaire\n\nid-1\n00:01.000 --> 00:03.000 align:start position:0%\n" +
+				"<v Bob><c.yellow>Hello</c> world.</v>\n\n00:00:04.000 --> 00:00:05.000\nBye.\n",
+			want: []Phrase{{TimestampMs: 1000, Text: "Hello world."}, {TimestampMs: 4000, Text: "Bye."}},
+		},
+		{
+			name:   "ttml",
+			format: model.FormatTTML,
+			input: `<?xml version="1.0"?><tt xmlns="http://www.w3.org/ns/ttml"><body><div>` +
+				`<p begin="00:00:01.000" end="00:00:02.000">Hello<br/>world.</p>` +
+				`<p begin="4s" dur="1s">Bye.</p></div></body></tt>`,
+			want: []Phrase{{TimestampMs: 1000, Text: "Hello world."}, {TimestampMs: 4000, Text: "Bye."}},
+		},
+		{
+			name:   "srv3",
+			format: model.FormatSRV3,
+			input: `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3"><body>` +
+				`<p t="1000" d="2000"><s ac="0">Hello</s><s t="500" ac="0"> world.</s></p>` +
+				`<p t="4000" d="1000">Bye.</p></body></timedtext>`,
+			want: []Phrase{{TimestampMs: 1000, Text: "Hello world."}, {TimestampMs: 4000, Text: "Bye."}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := ParseBytes([]byte(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("ParseBytes: %v", err)
+			}
+			got, err := TransformManualRawToPhrases(raw, Options{})
+			if err != nil {
+				t.Fatalf("transform: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d phrases %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if got[i].TimestampMs != w.TimestampMs || got[i].Text != w.Text {
+					t.Errorf("phrase %d = (%d, %q), want (%d, %q)", i, got[i].TimestampMs, got[i].Text, w.TimestampMs, w.Text)
+				}
+			}
+		})
+	}
+}
+
+// Sous-titres auto YouTube en VTT : lignes répétées sans timestamps inline à ignorer,
+// timestamps inline convertis en tOffsetMs.
+func TestParseVTTKaraoke(t *testing.T) {
+	input := "WEBVTT\n\n" +
+		"00:00:01.000 --> 00:00:03.000 align:start position:0%\n \nhello<00:00:01.500><c> world</c>\n\n" +
+		"00:00:03.000 --> 00:00:03.010\nhello world\n\n" +
+		"00:00:03.010 --> 00:00:05.000\nhello world\nhow<00:00:03.400><c> are</c><00:00:03.800><c> you</c>\n"
+	raw, err := ParseVTTBytes([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var words []string
+	var times []int64
+	for _, ev := range raw.Events {
+		for _, s := range ev.Segs {
+			words = append(words, s.Utf8)
+			times = append(times, calculateAbsTime(ev, s))
+		}
+	}
+	wantWords := []string{"hello", " world", "how", " are", " you"}
+	wantTimes := []int64{1000, 1500, 3010, 3400, 3800}
+	if len(words) != len(wantWords) {
+		t.Fatalf("segs = %q, want %q", words, wantWords)
+	}
+	for i := range wantWords {
+		if words[i] != wantWords[i] || times[i] != wantTimes[i] {
+			t.Errorf("seg %d = (%q, %d), want (%q, %d)", i, words[i], times[i], wantWords[i], wantTimes[i])
+		}
+	}
+}
+
+func TestParseJSON3Reader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		events  int
+		wantErr bool
+	}{
+		{"champs ignorés", `{"wireMagic":"pb3","pens":[{"bAttr":1}],"wsWinStyles":[{}],"events":[{"tStartMs":0,"segs":[{"utf8":"a"}]},{"tStartMs":10,"wWinId":1}]}`, 2, false},
+		{"events null", `{"wireMagic":"pb3","events":null}`, 0, false},
+		{"sans events", `{"wireMagic":"pb3"}`, 0, false},
+		{"tronqué", `{"events":[{"tStartMs":0,"segs":[{"utf8":"a"}]},{"tSt`, 0, true},
+		{"pas un objet", `[1,2]`, 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := ParseJSON3Reader(strings.NewReader(tc.input))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && len(raw.Events) != tc.events {
+				t.Errorf("%d events, want %d", len(raw.Events), tc.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package subtitles
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parse_srt.go : SRT -> rawJSON3. Un event par bloc, un seul seg avec le texte
+// du bloc (lignes jointes par un espace, balises retirées).
+//
+//	1
+//	00:00:01,000 --> 00:00:04,000
+//	Bonjour <i>à tous</i>
+
+// reCueTiming : ligne de timing SRT ou VTT, les réglages de cue après la fin sont ignorés.
+var reCueTiming = regexp.MustCompile(`^\s*(\S+)\s+-->\s+(\S+)`)
+
+// reMarkupTag : balises inline SRT/VTT (<i>, <font ...>, <c.color>, <v Nom>, </c>...).
+var reMarkupTag = regexp.MustCompile(`<[^>]*>`)
+
+// reVoiceTag : balise de voix WebVTT (<v Nom>, <v.classe Nom>), gardée comme
+// marque voiceMark + "Nom:" (voir voiceTurns).
+var reVoiceTag = regexp.MustCompile(`<v(?:\.[^\s>]*)?\s+([^>]+)>`)
+
+// ParseSRTBytes parse un fichier SRT et retourne la structure rawJSON3.
+func ParseSRTBytes(b []byte) (rawJSON3, error) {
+	var raw rawJSON3
+	if len(bytes.TrimSpace(b)) == 0 {
+		return raw, fmt.Errorf("ParseSRTBytes: empty input")
+	}
+	for i, block := range splitBlocks(b) {
+		// l'index numérique est optionnel en pratique : on cherche la ligne de timing
+		t := 0
+		for t < len(block) && !reCueTiming.MatchString(block[t]) {
+			t++
+		}
+		if t == len(block) {
+			continue
+		}
+		start, end, err := parseCueTiming(block[t])
+		if err != nil {
+			return raw, fmt.Errorf("ParseSRTBytes: bloc %d: %w", i+1, err)
+		}
+		text := cleanCueText(strings.Join(block[t+1:], " "))
+		if text == "" {
+			continue
+		}
+		raw.Events = append(raw.Events, newCueEvent(start, end, []rawSeg{{Utf8: text}}))
+	}
+	return raw, nil
+}
+
+// splitBlocks découpe le contenu en blocs séparés par des lignes vides
+// (BOM et fins de ligne \r\n gérés).
+func splitBlocks(b []byte) [][]string {
+	b = bytes.TrimPrefix(b, []byte("\ufeff"))
+	var blocks [][]string
+	var cur []string
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		// seule une ligne réellement vide sépare les blocs : les sous-titres auto
+		// de YouTube en VTT commencent par une ligne " " à l'intérieur de la cue
+		if line == "" {
+			if len(cur) > 0 {
+				blocks = append(blocks, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks
+}
+
+// parseCueTiming lit "début --> fin [réglages]" et retourne les bornes en ms.
+func parseCueTiming(line string) (int64, int64, error) {
+	m := reCueTiming.FindStringSubmatch(line)
+	if m == nil {
+		return 0, 0, fmt.Errorf("ligne de timing invalide: %q", line)
+	}
+	start, ok := parseClockMs(m[1])
+	if !ok {
+		return 0, 0, fmt.Errorf("timestamp invalide: %q", m[1])
+	}
+	end, ok := parseClockMs(m[2])
+	if !ok {
+		return 0, 0, fmt.Errorf("timestamp invalide: %q", m[2])
+	}
+	return start, end, nil
+}
+
+// parseClockMs lit "hh:mm:ss,mmm", "hh:mm:ss.mmm" ou "mm:ss.mmm" (VTT) en ms.
+func parseClockMs(s string) (int64, bool) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	whole, frac, _ := strings.Cut(s, ".")
+	parts := strings.Split(whole, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var total int64
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	ms := total * 1000
+	if frac != "" {
+		// "5" -> 500 ms, "05" -> 50 ms, "005" -> 5 ms
+		frac = (frac + "000")[:3]
+		n, err := strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		ms += n
+	}
+	return ms, true
+}
+
+// cleanCueText retire les balises inline (les balises de voix deviennent des marques
+// voiceMark, voir voiceTurns), décode les entités HTML et normalise les espaces.
+func cleanCueText(s string) string {
+	s = reVoiceTag.ReplaceAllString(s, voiceMark+"$1: ")
+	s = reMarkupTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	return normalizeWhitespace(s)
+}
+
+// newCueEvent construit un rawEvent à partir des bornes d'une cue.
+func newCueEvent(startMs, endMs int64, segs []rawSeg) rawEvent {
+	start := startMs
+	dur := max(endMs-startMs, 0)
+	return rawEvent{TStartMs: &start, DDurationMs: &dur, Segs: segs}
+}
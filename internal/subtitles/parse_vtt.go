@@ -0,0 +1,96 @@
+package subtitles
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// parse_vtt.go : WebVTT -> rawJSON3.
+//
+// Les réglages de cue ("align:start position:0%") sont ignorés, les balises inline
+// (<c.colorE5E5E5>, <i>, <v Nom>...) retirées. Les timestamps inline
+// (<00:00:01.500>, sous-titres auto "karaoké" de YouTube) découpent la cue en segs
+// avec tOffsetMs, comme dans json3.
+//
+// Dans ce mode karaoké, chaque cue répète la ligne précédente sans timestamps :
+// si le fichier contient des timestamps inline, seules les lignes qui en portent
+// sont gardées.
+
+// reInlineTimestamp : <hh:mm:ss.mmm> ou <mm:ss.mmm> dans le texte d'une cue.
+var reInlineTimestamp = regexp.MustCompile(`<((?:\d+:)?\d{2}:\d{2}\.\d{3})>`)
+
+// ParseVTTBytes parse un fichier WebVTT et retourne la structure rawJSON3.
+func ParseVTTBytes(b []byte) (rawJSON3, error) {
+	var raw rawJSON3
+	if len(bytes.TrimSpace(b)) == 0 {
+		return raw, fmt.Errorf("ParseVTTBytes: empty input")
+	}
+	blocks := splitBlocks(b)
+	if len(blocks) == 0 || !strings.HasPrefix(strings.TrimSpace(blocks[0][0]), "WEBVTT") {
+		return raw, fmt.Errorf("ParseVTTBytes: en-tête WEBVTT absent")
+	}
+	karaoke := reInlineTimestamp.Match(b)
+
+	for i, block := range blocks[1:] {
+		// NOTE, STYLE et REGION ne sont pas des cues
+		if first := strings.TrimSpace(block[0]); strings.HasPrefix(first, "NOTE") ||
+			first == "STYLE" || first == "REGION" {
+			continue
+		}
+		t := 0
+		for t < len(block) && !reCueTiming.MatchString(block[t]) {
+			t++ // identifiant de cue optionnel
+		}
+		if t == len(block) {
+			continue
+		}
+		start, end, err := parseCueTiming(block[t])
+		if err != nil {
+			return raw, fmt.Errorf("ParseVTTBytes: cue %d: %w", i+1, err)
+		}
+
+		var segs []rawSeg
+		for _, line := range block[t+1:] {
+			if karaoke && !reInlineTimestamp.MatchString(line) {
+				continue
+			}
+			segs = append(segs, splitInlineTimestamps(line, start)...)
+		}
+		if len(segs) == 0 {
+			continue
+		}
+		raw.Events = append(raw.Events, newCueEvent(start, end, segs))
+	}
+	return raw, nil
+}
+
+// splitInlineTimestamps découpe une ligne de cue sur ses timestamps inline.
+// Le premier morceau a un offset 0, les suivants l'offset du timestamp qui les précède.
+func splitInlineTimestamps(line string, cueStartMs int64) []rawSeg {
+	var segs []rawSeg
+	add := func(text string, offset int64) {
+		text = cleanCueText(text)
+		if text == "" {
+			return
+		}
+		off := offset
+		// espace initial : les transformations recollent les segs par un espace
+		if len(segs) > 0 {
+			text = " " + text
+		}
+		segs = append(segs, rawSeg{Utf8: text, TOffsetMs: &off})
+	}
+
+	prev, offset := 0, int64(0)
+	for _, m := range reInlineTimestamp.FindAllStringSubmatchIndex(line, -1) {
+		add(line[prev:m[0]], offset)
+		if ts, ok := parseClockMs(line[m[2]:m[3]]); ok {
+			offset = max(ts-cueStartMs, 0)
+		}
+		prev = m[1]
+	}
+	add(line[prev:], offset)
+	return segs
+}
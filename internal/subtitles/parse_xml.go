@@ -0,0 +1,205 @@
+package subtitles
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parse_xml.go : formats XML -> rawJSON3.
+//   - TTML (W3C Timed Text) : <p begin="..." end="..."|dur="...">texte<br/>suite</p>
+//   - srv3 (YouTube)        : <p t="ms" d="ms"><s t="offset">mot</s>...</p>
+//
+// Les deux sont lus en flux avec xml.Decoder : les espaces de noms sont ignorés
+// (on compare uniquement Name.Local).
+
+// ParseTTMLBytes parse un document TTML et retourne la structure rawJSON3.
+// Expressions de temps gérées : horloge ("00:01:02.500", "00:01:02:12" en frames
+// à 30 fps) et offsets ("1.5s", "1500ms", "2m", "1h", "300t" selon ttp:tickRate).
+func ParseTTMLBytes(b []byte) (rawJSON3, error) {
+	var raw rawJSON3
+	if len(bytes.TrimSpace(b)) == 0 {
+		return raw, fmt.Errorf("ParseTTMLBytes: empty input")
+	}
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	tickRate := int64(0)
+
+	var (
+		inP        bool
+		start, end int64
+		text       strings.Builder
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return raw, fmt.Errorf("ParseTTMLBytes: %w", err)
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "tt":
+				if v := xmlAttr(el, "tickRate"); v != "" {
+					tickRate, _ = strconv.ParseInt(v, 10, 64)
+				}
+			case "p":
+				begin, okB := parseTTMLTime(xmlAttr(el, "begin"), tickRate)
+				if !okB {
+					return raw, fmt.Errorf("ParseTTMLBytes: begin invalide: %q", xmlAttr(el, "begin"))
+				}
+				start, end = begin, begin
+				if v, ok := parseTTMLTime(xmlAttr(el, "end"), tickRate); ok {
+					end = v
+				} else if d, ok := parseTTMLTime(xmlAttr(el, "dur"), tickRate); ok {
+					end = begin + d
+				}
+				inP = true
+				text.Reset()
+			case "br":
+				if inP {
+					text.WriteByte(' ')
+				}
+			}
+		case xml.CharData:
+			if inP {
+				text.Write(el)
+			}
+		case xml.EndElement:
+			if el.Name.Local == "p" && inP {
+				inP = false
+				if t := normalizeWhitespace(text.String()); t != "" {
+					raw.Events = append(raw.Events, newCueEvent(start, end, []rawSeg{{Utf8: t}}))
+				}
+			}
+		}
+	}
+	return raw, nil
+}
+
+// parseTTMLTime convertit une expression de temps TTML en ms.
+func parseTTMLTime(s string, tickRate int64) (int64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	if strings.Contains(s, ":") {
+		// "hh:mm:ss:frames" : les frames sont converties à 30 fps
+		if parts := strings.Split(s, ":"); len(parts) == 4 {
+			ms, ok := parseClockMs(strings.Join(parts[:3], ":"))
+			frames, err := strconv.ParseInt(parts[3], 10, 64)
+			if !ok || err != nil {
+				return 0, false
+			}
+			return ms + frames*1000/30, true
+		}
+		return parseClockMs(s)
+	}
+
+	units := []struct {
+		suffix string
+		ms     float64
+	}{{"ms", 1}, {"h", 3_600_000}, {"m", 60_000}, {"s", 1000}, {"t", 0}}
+	for _, u := range units {
+		num, ok := strings.CutSuffix(s, u.suffix)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(num, 64)
+		if err != nil || v < 0 {
+			return 0, false
+		}
+		if u.suffix == "t" {
+			if tickRate <= 0 {
+				tickRate = 1 // valeur par défaut de TTML sans frameRate
+			}
+			return int64(v * 1000 / float64(tickRate)), true
+		}
+		return int64(v * u.ms), true
+	}
+	return 0, false
+}
+
+// ParseSRV3Bytes parse un document srv3 (YouTube "timedtext format 3").
+// Chaque <p> devient un event ; ses <s> deviennent des segs avec tOffsetMs,
+// le texte hors <s> un seg sans offset. a="1" est reporté dans aAppend.
+func ParseSRV3Bytes(b []byte) (rawJSON3, error) {
+	var raw rawJSON3
+	if len(bytes.TrimSpace(b)) == 0 {
+		return raw, fmt.Errorf("ParseSRV3Bytes: empty input")
+	}
+	dec := xml.NewDecoder(bytes.NewReader(b))
+
+	var (
+		ev     *rawEvent
+		inS    bool
+		offset *int64
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return raw, fmt.Errorf("ParseSRV3Bytes: %w", err)
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "p":
+				t, err := strconv.ParseInt(xmlAttr(el, "t"), 10, 64)
+				if err != nil {
+					return raw, fmt.Errorf("ParseSRV3Bytes: attribut t invalide: %q", xmlAttr(el, "t"))
+				}
+				ev = &rawEvent{TStartMs: &t}
+				if d, err := strconv.ParseInt(xmlAttr(el, "d"), 10, 64); err == nil {
+					ev.DDurationMs = &d
+				}
+				if xmlAttr(el, "a") == "1" {
+					one := 1
+					ev.AAppend = &one
+				}
+			case "s":
+				inS = true
+				offset = nil
+				if v, err := strconv.ParseInt(xmlAttr(el, "t"), 10, 64); err == nil {
+					offset = &v
+				}
+			}
+		case xml.CharData:
+			if ev == nil || len(el) == 0 {
+				continue
+			}
+			seg := rawSeg{Utf8: string(el)}
+			if inS {
+				seg.TOffsetMs = offset
+			}
+			ev.Segs = append(ev.Segs, seg)
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "s":
+				inS = false
+			case "p":
+				if ev != nil && len(ev.Segs) > 0 {
+					raw.Events = append(raw.Events, *ev)
+				}
+				ev = nil
+			}
+		}
+	}
+	return raw, nil
+}
+
+// xmlAttr retourne la valeur de l'attribut `local` (espace de noms ignoré).
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
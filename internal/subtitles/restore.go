@@ -0,0 +1,409 @@
+package subtitles
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// restore.go : restauration hors ligne de la ponctuation et de la casse des
+// captions ASR livrées en minuscules sans ponctuation. Sans ponctuation,
+// TransformAutoRawToPhrases ne coupe que sur les pauses de 2 s et à 100 mots.
+//
+// Ici on recoupe le flux de mots (Phrase.Words) en phrases à partir :
+//   - des pauses relatives : intervalle entre deux débuts de mots comparé à l'intervalle médian ;
+//   - d'un lexique par langue des mots qui ouvrent souvent une phrase ("so", "alors"...)
+//     et de ceux qui ne la terminent jamais ("the", "de"...) ;
+//   - des noms propres trouvés dans le titre, la description et les tags (ProperNounLexicon).
+// Chaque phrase reçoit une majuscule initiale et un point final.
+
+// RestoreMode : quand appliquer la restauration.
+type RestoreMode string
+
+const (
+	RestoreOff    RestoreMode = "off"    // jamais
+	RestoreAuto   RestoreMode = "auto"   // si les captions semblent non ponctuées (NeedsRestoration)
+	RestoreAlways RestoreMode = "always" // toujours (sous-titres automatiques uniquement)
+)
+
+// ParseRestoreMode convertit la valeur de config ("off" par défaut).
+func ParseRestoreMode(s string) (RestoreMode, error) {
+	switch s {
+	case "auto":
+		return RestoreAuto, nil
+	case "", "off":
+		return RestoreOff, nil
+	case "always":
+		return RestoreAlways, nil
+	default:
+		return "", fmt.Errorf("mode de restauration inconnu: %s", s)
+	}
+}
+
+// RestoreOptions : réglages de RestorePunctuation.
+type RestoreOptions struct {
+	Lang        string            // langue des captions (lexiques, "i" -> "I" en anglais)
+	ProperNouns map[string]string // minuscules -> forme d'origine (voir ProperNounLexicon)
+	PauseFactor float64           // pause > PauseFactor × intervalle médian : fin de phrase probable
+	MinWords    int               // pas de coupure avant MinWords mots
+	MaxWords    int               // coupure forcée sur la meilleure frontière au-delà
+}
+
+// DefaultRestoreOptions : réglages par défaut (repris par la config).
+func DefaultRestoreOptions() RestoreOptions {
+	return RestoreOptions{PauseFactor: 2.5, MinWords: 4, MaxWords: 30}
+}
+
+// lexique des mots qui ouvrent souvent une phrase parlée
+var sentenceStarters = map[string][]string{
+	"en": {"so", "now", "okay", "ok", "well", "then", "but", "today", "first", "next", "finally",
+		"also", "however", "actually", "anyway", "let's", "that's", "it's", "i", "we", "you", "if"},
+	"fr": {"alors", "donc", "mais", "ensuite", "maintenant", "bon", "voilà", "enfin", "bref",
+		"aujourd'hui", "d'abord", "puis", "je", "on", "nous", "c'est", "si"},
+	"de": {"also", "dann", "jetzt", "aber", "ich", "wir", "heute", "okay", "so", "zuerst", "danach", "wenn"},
+	"es": {"entonces", "pero", "bueno", "ahora", "hoy", "yo", "vamos", "luego", "después", "primero", "si"},
+}
+
+// lexique des mots qui ne terminent (presque) jamais une phrase : articles,
+// prépositions, conjonctions, pronoms sujets
+var nonFinalWords = map[string][]string{
+	"en": {"the", "a", "an", "and", "or", "but", "of", "to", "in", "on", "at", "for", "with", "from",
+		"my", "your", "our", "their", "his", "her", "its", "is", "are", "was", "were", "i", "we",
+		"you", "they", "he", "she", "that", "which", "because", "if", "so", "very"},
+	"fr": {"le", "la", "les", "l'", "un", "une", "des", "de", "du", "d'", "et", "ou", "mais", "à", "au",
+		"aux", "en", "pour", "avec", "dans", "sur", "par", "je", "tu", "il", "elle", "on", "nous",
+		"vous", "ils", "ce", "cette", "que", "qui", "est", "très", "mon", "ton", "son"},
+	"de": {"der", "die", "das", "den", "dem", "ein", "eine", "einen", "und", "oder", "aber", "zu",
+		"mit", "von", "für", "auf", "in", "ich", "wir", "du", "er", "sie", "ist", "sehr"},
+	"es": {"el", "la", "los", "las", "un", "una", "y", "o", "pero", "de", "del", "a", "al", "en",
+		"con", "por", "para", "que", "yo", "es", "muy", "mi", "su"},
+}
+
+// lexicon retourne l'ensemble des entrées de table pour la langue, ou l'union
+// de toutes les langues si elle est inconnue.
+func lexicon(table map[string][]string, lang string) map[string]struct{} {
+	var words []string
+	if list, ok := table[model.BaseLang(lang)]; ok {
+		words = list
+	} else {
+		for _, l := range slices.Sorted(maps.Keys(table)) {
+			words = append(words, table[l]...)
+		}
+	}
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// NeedsRestoration : les phrases ressemblent à des captions brutes — au moins
+// 20 mots, moins d'une ponctuation finale tous les 40 mots et presque pas de majuscules.
+func NeedsRestoration(phrases []Phrase) bool {
+	words, terminated, upper := 0, 0, 0
+	for _, p := range phrases {
+		for _, f := range strings.Fields(p.Text) {
+			words++
+			if r, ok := lastNonSpaceRune(trimTrailingClosers(f)); ok && isSentenceTerminatorRune(r) {
+				terminated++
+			}
+			if r, _ := utf8.DecodeRuneInString(f); unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return words >= 20 && terminated*40 < words && upper*20 < words
+}
+
+// RestorePunctuation recoupe les phrases (captions auto) en phrases ponctuées.
+// Les timings par mot sont conservés ; une phrase sans Words est découpée sur
+// ses espaces, tous ses mots au début de la phrase. Chaque réplique (Speaker) est
+// traitée séparément : une phrase ne traverse jamais un changement de locuteur.
+func RestorePunctuation(phrases []Phrase, opts RestoreOptions) []Phrase {
+	var out []Phrase
+	for start := 0; start < len(phrases); {
+		end := start + 1
+		for end < len(phrases) && phrases[end].Speaker == phrases[start].Speaker {
+			end++
+		}
+		for _, p := range restoreTurn(phrases[start:end], opts) {
+			p.Speaker = phrases[start].Speaker
+			out = append(out, p)
+		}
+		start = end
+	}
+	return out
+}
+
+// restoreTurn : RestorePunctuation sur les phrases d'un même locuteur.
+func restoreTurn(phrases []Phrase, opts RestoreOptions) []Phrase {
+	words := flattenWords(phrases)
+	if len(words) == 0 {
+		return phrases
+	}
+	if opts.PauseFactor <= 0 {
+		opts.PauseFactor = DefaultRestoreOptions().PauseFactor
+	}
+	opts.MinWords = max(opts.MinWords, 1)
+	opts.MaxWords = max(opts.MaxWords, opts.MinWords)
+
+	starters := lexicon(sentenceStarters, opts.Lang)
+	nonFinal := lexicon(nonFinalWords, opts.Lang)
+	median := medianGapMs(words)
+
+	// score de la frontière après words[i] (0 : pas de coupure possible)
+	score := func(i int) float64 {
+		cur := bareWord(words[i].Text)
+		if _, ok := nonFinal[cur]; ok {
+			return 0
+		}
+		if r, ok := lastNonSpaceRune(trimTrailingClosers(words[i].Text)); ok && isSentenceTerminatorRune(r) {
+			return 100 // déjà ponctué
+		}
+		s := float64(words[i+1].StartMs-words[i].StartMs) / median
+		if _, ok := starters[bareWord(words[i+1].Text)]; ok {
+			s += opts.PauseFactor / 2
+		}
+		return s
+	}
+
+	var out []Phrase
+	start := 0
+	for start < len(words) {
+		end := len(words) // fin exclusive de la phrase courante
+		best, bestScore := -1, 0.0
+		for i := start; i < len(words)-1; i++ {
+			n := i - start + 1
+			if n >= opts.MinWords {
+				s := score(i)
+				if s >= opts.PauseFactor {
+					end = i + 1
+					break
+				}
+				if s > bestScore {
+					best, bestScore = i, s
+				}
+			}
+			if n >= opts.MaxWords {
+				end = i + 1
+				if best >= 0 {
+					end = best + 1
+				}
+				break
+			}
+		}
+		out = append(out, restoredPhrase(words[start:end], opts))
+		start = end
+	}
+	return out
+}
+
+// flattenWords : mots de toutes les phrases, dans l'ordre.
+func flattenWords(phrases []Phrase) []Word {
+	var words []Word
+	for _, p := range phrases {
+		if len(p.Words) > 0 {
+			words = append(words, p.Words...)
+			continue
+		}
+		for _, f := range strings.Fields(p.Text) {
+			words = append(words, Word{Text: f, StartMs: p.TimestampMs, EndMs: p.TimestampMs})
+		}
+	}
+	return words
+}
+
+// medianGapMs : intervalle médian entre débuts de mots consécutifs (>= 1).
+func medianGapMs(words []Word) float64 {
+	gaps := make([]int64, 0, len(words))
+	for i := 0; i+1 < len(words); i++ {
+		if g := words[i+1].StartMs - words[i].StartMs; g > 0 {
+			gaps = append(gaps, g)
+		}
+	}
+	if len(gaps) == 0 {
+		return 1
+	}
+	slices.Sort(gaps)
+	return float64(max(gaps[len(gaps)/2], 1))
+}
+
+// bareWord : mot en minuscules sans ponctuation autour.
+func bareWord(s string) string {
+	return strings.ToLower(strings.TrimFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}))
+}
+
+// restoredPhrase construit la phrase : noms propres, "I" anglais, majuscule
+// initiale et point final si aucune ponctuation finale.
+func restoredPhrase(words []Word, opts RestoreOptions) Phrase {
+	ws := make([]Word, len(words))
+	texts := make([]string, len(words))
+	english := model.BaseLang(opts.Lang) == "en"
+	for i, w := range words {
+		t := strings.TrimSpace(w.Text)
+		if cased, ok := opts.ProperNouns[bareWord(t)]; ok {
+			t = strings.Replace(t, bareWordRaw(t), cased, 1)
+		} else if english && (bareWord(t) == "i" || strings.HasPrefix(bareWord(t), "i'")) {
+			// seul le mot change : "(i'm" -> "(I'm"
+			raw := bareWordRaw(t)
+			t = strings.Replace(t, raw, "I"+raw[1:], 1)
+		}
+		if i == 0 {
+			t = capitalizeFirst(t)
+		}
+		w.Text = t
+		ws[i] = w
+		texts[i] = t
+	}
+	last := texts[len(texts)-1]
+	if r, ok := lastNonSpaceRune(trimTrailingClosers(last)); !ok || !isSentenceTerminatorRune(r) {
+		texts[len(texts)-1] = last + "."
+		ws[len(ws)-1].Text = texts[len(texts)-1]
+	}
+	text := strings.Join(texts, phraseJoiner(opts.Lang))
+	return Phrase{
+		TimestampMs: ws[0].StartMs,
+		EndMs:       ws[len(ws)-1].EndMs,
+		Text:        text,
+		RuneCount:   utf8.RuneCountInString(text),
+		WordCount:   CountWords(text, opts.Lang),
+		Words:       ws,
+	}
+}
+
+// bareWordRaw : comme bareWord mais sans changer la casse (pour le remplacement).
+func bareWordRaw(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// capitalizeFirst met en majuscule la première lettre de s.
+func capitalizeFirst(s string) string {
+	for i, r := range s {
+		if unicode.IsLetter(r) {
+			return s[:i] + string(unicode.ToUpper(r)) + s[i+utf8.RuneLen(r):]
+		}
+	}
+	return s
+}
+
+// ProperNounLexicon extrait les noms propres des textes (titre, description, tags) :
+// les mots capitalisés qui n'ouvrent pas une phrase, sauf s'ils apparaissent plus
+// souvent en minuscules (les tags le sont souvent). Dans un texte en "Title Case"
+// (la plupart des mots capitalisés), seuls les sigles et les mots à majuscule
+// interne ("GPU", "GitHub") sont retenus. Un mot à simple majuscule doit être vu
+// capitalisé au moins minProperNounCount fois : un seul "Go" ne suffit pas à
+// capitaliser chaque "go" prononcé.
+func ProperNounLexicon(texts ...string) map[string]string {
+	const minProperNounCount = 2
+	lowerCount := make(map[string]int)
+	upperCount := make(map[string]int)
+	forms := make(map[string]string)
+	for _, text := range texts {
+		for _, line := range strings.Split(text, "\n") {
+			fields := strings.Fields(line)
+			capitalized := 0
+			for _, f := range fields {
+				if r, _ := utf8.DecodeRuneInString(bareWordRaw(f)); unicode.IsUpper(r) {
+					capitalized++
+				}
+			}
+			titleCase := len(fields) >= 3 && capitalized*10 >= len(fields)*6
+
+			sentenceStart := true
+			for _, f := range fields {
+				w := bareWordRaw(f)
+				r, _ := utf8.DecodeRuneInString(w)
+				switch {
+				case w == "" || !unicode.IsLetter(r):
+				case !unicode.IsUpper(r):
+					lowerCount[strings.ToLower(w)]++
+				case utf8.RuneCountInString(w) < 2:
+				case titleCase && !hasInnerUpper(w):
+				case sentenceStart && !hasInnerUpper(w):
+				default:
+					upperCount[strings.ToLower(w)]++
+					forms[strings.ToLower(w)] = w
+				}
+				last, _ := lastNonSpaceRune(trimTrailingClosers(f))
+				sentenceStart = isSentenceTerminatorRune(last) || last == ':'
+			}
+		}
+	}
+	for l, n := range upperCount {
+		if lowerCount[l] > n || (n < minProperNounCount && !hasInnerUpper(forms[l])) {
+			delete(forms, l)
+		}
+	}
+	return forms
+}
+
+// hasInnerUpper : une majuscule après la première lettre ("GitHub", "GPU").
+func hasInnerUpper(w string) bool {
+	for i, r := range w {
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// BoundaryScore : qualité des frontières de phrases d'une découpe par rapport à
+// une référence (ex. sous-titres manuels de la même vidéo).
+type BoundaryScore struct {
+	Precision, Recall, F1 float64
+}
+
+func (s BoundaryScore) String() string {
+	return fmt.Sprintf("précision %.2f, rappel %.2f, F1 %.2f", s.Precision, s.Recall, s.F1)
+}
+
+// ScoreBoundaries compare les débuts de phrases (hors première) de hyp à ceux de
+// ref : une frontière est juste si elle tombe à toleranceMs près d'une frontière
+// de référence non encore appariée.
+func ScoreBoundaries(ref, hyp []Phrase, toleranceMs int64) BoundaryScore {
+	starts := func(ps []Phrase) []int64 {
+		var out []int64
+		for i, p := range ps {
+			if i > 0 {
+				out = append(out, p.TimestampMs)
+			}
+		}
+		slices.Sort(out)
+		return out
+	}
+	r, h := starts(ref), starts(hyp)
+	matched := 0
+	i, j := 0, 0
+	for i < len(r) && j < len(h) {
+		switch d := h[j] - r[i]; {
+		case absInt64(d) <= toleranceMs:
+			matched++
+			i++
+			j++
+		case d < 0:
+			j++
+		default:
+			i++
+		}
+	}
+	var s BoundaryScore
+	if len(h) > 0 {
+		s.Precision = float64(matched) / float64(len(h))
+	}
+	if len(r) > 0 {
+		s.Recall = float64(matched) / float64(len(r))
+	}
+	if s.Precision+s.Recall > 0 {
+		s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixtures : même discours en sous-titres manuels ("<nom>.srt", ponctués) et en
+// captions auto ("<nom>.json3", sans ponctuation, timings par mot). restore_talk
+// est écrit à la main ; une paire capturée sur YouTube (yt-dlp --write-subs
+// --write-auto-subs --sub-format srt/json3) se dépose à côté sous le même motif
+// et passe par TestRestorePunctuationFixtures.
+func loadRestoreFixture(t *testing.T, name string) (manual, auto []Phrase) {
+	t.Helper()
+	read := func(file string) []byte {
+		b, err := os.ReadFile(filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	lang := strings.TrimPrefix(filepath.Ext(name), ".")
+	rawManual, err := ParseSRTBytes(read(name + ".srt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manual, err = TransformManualRawToPhrases(rawManual, Options{Lang: lang})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawAuto, err := ParseJSON3Bytes(read(name + ".json3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auto, err = TransformAutoRawToPhrases(rawAuto, Options{Lang: lang})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manual, auto
+}
+
+func TestRestorePunctuationFixtures(t *testing.T) {
+	srts, err := filepath.Glob(filepath.Join("testdata", "restore_*.srt"))
+	if err != nil || len(srts) == 0 {
+		t.Fatalf("aucune fixture restore_* : %v", err)
+	}
+	for _, srt := range srts {
+		name := strings.TrimSuffix(filepath.Base(srt), ".srt") // "restore_talk.en"
+		t.Run(name, func(t *testing.T) {
+			manual, auto := loadRestoreFixture(t, name)
+			opts := DefaultRestoreOptions()
+			opts.Lang = strings.TrimPrefix(filepath.Ext(name), ".")
+			base := ScoreBoundaries(manual, auto, 400)
+			got := ScoreBoundaries(manual, RestorePunctuation(auto, opts), 400)
+			t.Logf("sans restauration : %s ; avec : %s", base, got)
+			if got.F1 <= base.F1 {
+				t.Errorf("F1 restauration = %.2f (base %.2f)", got.F1, base.F1)
+			}
+		})
+	}
+}
+
+func TestRestorePunctuationAgainstManual(t *testing.T) {
+	manual, auto := loadRestoreFixture(t, "restore_talk.en")
+	if !NeedsRestoration(auto) {
+		t.Fatalf("captions brutes non détectées : %d phrases", len(auto))
+	}
+	if NeedsRestoration(manual) {
+		t.Errorf("sous-titres manuels détectés comme non ponctués")
+	}
+
+	opts := DefaultRestoreOptions()
+	opts.Lang = "en"
+	opts.ProperNouns = ProperNounLexicon(
+		"Deploying Go Services On Kubernetes From Berlin",
+		"In this video I deploy a Go service to Kubernetes with GitHub Actions. The Go code and the Kubernetes manifests are linked below.",
+		"kubernetes tutorial, GitHub, CPU")
+	restored := RestorePunctuation(auto, opts)
+
+	base := ScoreBoundaries(manual, auto, 400)
+	got := ScoreBoundaries(manual, restored, 400)
+	t.Logf("sans restauration : %s ; avec : %s", base, got)
+	if got.F1 < 0.8 || got.F1 <= base.F1 {
+		t.Errorf("F1 restauration = %.2f (base %.2f)", got.F1, base.F1)
+	}
+
+	text := Transcript{Phrases: restored}.CollapsedNoChapters()
+	for _, want := range []string{"Today we are going", "Go services on Kubernetes.", "GitHub", "I moved"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("texte restauré sans %q :\n%s", want, text)
+		}
+	}
+}
+
+func TestProperNounLexicon(t *testing.T) {
+	lex := ProperNounLexicon(
+		"How To Build A Tech Stack With GitHub And AWS", // Title Case : sigles seulement
+		"Today I visited Paris. The city was calm, and the Louvre was closed. We loved Paris in winter.",
+		"I wrote the tour planner in Go.", // un seul "Go" : pas assez pour "go" -> "Go"
+		"paris tips, louvre, louvre")
+	for lower, want := range map[string]string{"github": "GitHub", "aws": "AWS", "paris": "Paris"} {
+		if lex[lower] != want {
+			t.Errorf("lex[%q] = %q; want %q", lower, lex[lower], want)
+		}
+	}
+	// "louvre" : plus souvent en minuscules que capitalisé
+	for _, absent := range []string{"to", "build", "the", "today", "louvre", "go"} {
+		if _, ok := lex[absent]; ok {
+			t.Errorf("%q ne devrait pas être un nom propre", absent)
+		}
+	}
+}
+
+func TestRestoredPhraseEnglishI(t *testing.T) {
+	words := []Word{{Text: "so"}, {Text: `"i`}, {Text: `think"`}, {Text: "(i'm"}, {Text: "sure)"}}
+	got := restoredPhrase(words, RestoreOptions{Lang: "en"}).Text
+	if want := `So "I think" (I'm sure).`; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
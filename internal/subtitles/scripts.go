@@ -0,0 +1,108 @@
+package subtitles
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// scripts.go : ce qui dépend de l'écriture de la langue (SubtitleTrack.Lang).
+//   - japonais, chinois, thaï, lao, khmer, birman : pas d'espace entre les mots,
+//     les morceaux de phrase sont recollés sans espace ;
+//   - le nombre de mots y est estimé par écriture (voir countWordsByScript).
+
+// spacelessLangs : langues écrites sans espace entre les mots.
+var spacelessLangs = map[string]struct{}{
+	"ja": {}, "zh": {}, "yue": {}, "wuu": {}, "th": {}, "lo": {}, "km": {}, "my": {},
+}
+
+// spacelessRunesPerWord : longueur moyenne supposée d'un mot thaï, lao, khmer ou
+// birman (pas de dictionnaire pour les segmenter).
+const spacelessRunesPerWord = 3
+
+// IsSpacelessLang : la langue (de base) s'écrit sans espace entre les mots.
+func IsSpacelessLang(lang string) bool {
+	_, ok := spacelessLangs[model.BaseLang(lang)]
+	return ok
+}
+
+// phraseJoiner : séparateur entre deux morceaux d'une même phrase.
+func phraseJoiner(lang string) string {
+	if IsSpacelessLang(lang) {
+		return ""
+	}
+	return " "
+}
+
+// CountWords compte les mots de text selon la langue : strings.Fields pour les
+// langues à espaces, countWordsByScript pour les autres et si la langue est inconnue.
+func CountWords(text, lang string) int {
+	if lang != "" && !IsSpacelessLang(lang) {
+		return len(strings.Fields(text))
+	}
+	return countWordsByScript(text)
+}
+
+// countWordsByScript : un idéogramme ou un kana compte pour un mot, une suite de
+// lettres thaï/lao/khmer/birmanes pour un mot toutes les spacelessRunesPerWord
+// lettres, toute autre suite de lettres ou chiffres pour un mot.
+func countWordsByScript(text string) int {
+	n := 0
+	run := 0        // longueur de la suite de lettres thaï/lao/khmer/birmanes en cours
+	inWord := false // dans une suite de lettres "à espaces"
+	flushRun := func() {
+		if run > 0 {
+			n += (run + spacelessRunesPerWord - 1) / spacelessRunesPerWord
+			run = 0
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			flushRun()
+			inWord = false
+			n++
+		case unicode.In(r, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar):
+			inWord = false
+			if unicode.IsLetter(r) {
+				run++
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			flushRun()
+			if !inWord {
+				n++
+				inWord = true
+			}
+		default:
+			flushRun()
+			// apostrophe, trait d'union et séparateurs décimaux restent dans le mot
+			// ("l'homme", "peut-être", "2.6")
+			if !strings.ContainsRune("'’-.,", r) {
+				inWord = false
+			}
+		}
+	}
+	flushRun()
+	return n
+}
+
+// isSpacelessTerminatorRune : ponctuation finale pleine chasse (。！？), jamais
+// suivie d'un espace : elle termine la phrase même collée au mot suivant.
+func isSpacelessTerminatorRune(r rune) bool {
+	switch r {
+	case '。', '！', '？', '｡', '．':
+		return true
+	}
+	return false
+}
+
+// isOpenerRune : ponctuation ouvrante ignorée avant le mot suivant (¿qué?, «Oui», 「はい」).
+func isOpenerRune(r rune) bool {
+	switch r {
+	case '¿', '¡', '«', '‹', '"', '\'', '“', '‘', '„', '(', '[', '{',
+		'「', '『', '（', '【', '〔', '〈', '《', '〖', '〘', '〚', '［', '｛':
+		return true
+	}
+	return false
+}
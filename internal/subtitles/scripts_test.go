@@ -0,0 +1,72 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitSegOffsetsString_Scripts(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		in   string
+		want []string
+	}{
+		{"japonais sans espace", "ja", "今日は晴れです。明日は雨でしょう！", []string{"今日は晴れです。", "明日は雨でしょう！"}},
+		{"japonais guillemets", "ja", "彼は「はい。」と言った？それから", []string{"彼は「はい。」", "と言った？", "それから"}},
+		{"chinois", "zh-Hans", "你好。你好吗？", []string{"你好。", "你好吗？"}},
+		{"arabe", "ar", "كيف حالك؟ أنا بخير.", []string{"كيف حالك؟", "أنا بخير."}},
+		{"ourdou", "ur", "یہ اچھا ہے۔ شکریہ", []string{"یہ اچھا ہے۔", "شکریہ"}},
+		{"hindi", "hi", "मैं ठीक हूँ। धन्यवाद।", []string{"मैं ठीक हूँ।", "धन्यवाद।"}},
+		{"espagnol inversé", "es", "¿Qué tal? ¡Muy bien! Gracias.", []string{"¿Qué tal?", "¡Muy bien!", "Gracias."}},
+		{"espagnol ellipse puis ¿minuscule", "es", "Bueno... ¿qué hacemos?", []string{"Bueno... ¿qué hacemos?"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := splitSegOffsetsString(tc.in, Options{Lang: tc.lang}.splitRules())
+			var got []string
+			for _, p := range parts {
+				got = append(got, p.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		text string
+		lang string
+		want int
+	}{
+		{"Hello world, this is fine.", "en", 5},
+		{"l'homme est peut-être là", "fr", 4},
+		{"今日は晴れです。", "ja", 7},
+		{"我喜欢 Go 语言", "zh", 6},
+		{"สวัสดีครับ", "th", 3},
+		{"今日は晴れ", "", 5}, // langue inconnue : comptage par écriture
+		{"2.6 meters", "", 2},
+	}
+	for _, tc := range tests {
+		if got := CountWords(tc.text, tc.lang); got != tc.want {
+			t.Errorf("CountWords(%q, %q) = %d; want %d", tc.text, tc.lang, got, tc.want)
+		}
+	}
+}
+
+func TestAutoPhrasesSpacelessJoin(t *testing.T) {
+	raw := rawJSON3{Events: []rawEvent{
+		{TStartMs: ptrInt64(100), DDurationMs: ptrInt64(2000), Segs: []rawSeg{
+			{Utf8: "今日は"}, {Utf8: "晴れ", TOffsetMs: ptrInt64(500)}, {Utf8: "です。", TOffsetMs: ptrInt64(900)},
+		}},
+	}}
+	phrases, err := TransformAutoRawToPhrases(raw, Options{Lang: "ja"})
+	if err != nil || len(phrases) != 1 {
+		t.Fatalf("phrases=%#v err=%v", phrases, err)
+	}
+	if phrases[0].Text != "今日は晴れです。" || phrases[0].WordCount != 7 {
+		t.Errorf("phrase = %q (%d mots)", phrases[0].Text, phrases[0].WordCount)
+	}
+}
@@ -0,0 +1,174 @@
+package subtitles
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// SegmentMode : que faire des phrases situées dans un segment SponsorBlock.
+type SegmentMode string
+
+const (
+	SegmentModeStrip SegmentMode = "strip" // retirer les phrases du transcript
+	SegmentModeTag   SegmentMode = "tag"   // garder les phrases, marquées par Phrase.Segment
+)
+
+// ParseSegmentMode convertit la valeur de config en SegmentMode ("strip" par défaut).
+func ParseSegmentMode(s string) (SegmentMode, error) {
+	switch s {
+	case "", "strip":
+		return SegmentModeStrip, nil
+	case "tag":
+		return SegmentModeTag, nil
+	default:
+		return "", fmt.Errorf("mode sponsorblock inconnu: %s", s)
+	}
+}
+
+// SegmentReport résume l'effet des segments sur le transcript.
+type SegmentReport struct {
+	Phrases    int              // nombre de phrases retirées (strip) ou marquées (tag)
+	Clipped    int              // phrases écourtées de leurs mots dans un segment (strip)
+	ByCategory map[string]int64 // durée cumulée (ms) par catégorie
+}
+
+// phraseIndexAtOrAfter : recherche binaire du premier index dont TimestampMs >= ts.
+// Retourne len(phrases) si aucune phrase ne commence après ts.
+func phraseIndexAtOrAfter(phrases []Phrase, ts int64) int {
+	idx, _ := slices.BinarySearchFunc(phrases, ts, func(p Phrase, key int64) int {
+		if p.TimestampMs < key {
+			return -1
+		}
+		if p.TimestampMs > key {
+			return 1
+		}
+		return 0
+	})
+	// BinarySearchFunc peut tomber sur n'importe quelle égalité : revenir à la première
+	for idx > 0 && phrases[idx-1].TimestampMs >= ts {
+		idx--
+	}
+	return idx
+}
+
+// ApplySegments retire ou marque les phrases qui chevauchent un segment :
+//   - une phrase qui commence dans un segment, ou qui commence avant et y passe
+//     plus de la moitié de sa durée, est retirée (strip) ou marquée (tag) ;
+//   - en mode strip, une phrase aux timings par mot fiables est plutôt écourtée :
+//     seuls ses mots qui commencent dans un segment sont retirés (clipPhrase).
+//
+// Les phrases sont triées si besoin (ensureSortedPhrases) ; la slice retournée
+// est une nouvelle slice, l'entrée n'est pas modifiée.
+func ApplySegments(phrases []Phrase, segs []model.Segment, mode SegmentMode) ([]Phrase, SegmentReport) {
+	report := SegmentReport{ByCategory: make(map[string]int64)}
+	out := make([]Phrase, len(phrases))
+	copy(out, phrases)
+	if len(out) == 0 || len(segs) == 0 {
+		return out, report
+	}
+	ensureSortedPhrases(out)
+	ends := phraseEnds(out)
+
+	// segments chevauchant chaque phrase, dans l'ordre de segs
+	hits := make([][]model.Segment, len(out))
+	for _, seg := range segs {
+		if seg.DurationMs() == 0 {
+			continue
+		}
+		report.ByCategory[seg.Category] += seg.DurationMs()
+		first := phraseIndexAtOrAfter(out, seg.StartMs)
+		for i := first - 1; i >= 0 && ends[i] > seg.StartMs; i-- {
+			hits[i] = append(hits[i], seg)
+		}
+		for i := first; i < len(out) && out[i].TimestampMs < seg.EndMs; i++ {
+			hits[i] = append(hits[i], seg)
+		}
+	}
+
+	// covered : catégorie du premier segment qui couvre la phrase ("" = aucun)
+	covered := func(i int) string {
+		for _, seg := range hits[i] {
+			start, end := out[i].TimestampMs, ends[i]
+			overlap := min(end, seg.EndMs) - max(start, seg.StartMs)
+			if seg.Contains(start) || 2*overlap > end-start {
+				return seg.Category
+			}
+		}
+		return ""
+	}
+
+	if mode == SegmentModeTag {
+		for i := range out {
+			if cat := covered(i); cat != "" {
+				out[i].Segment = cat
+				report.Phrases++
+			}
+		}
+		return out, report
+	}
+
+	kept := out[:0]
+	for i, p := range out {
+		if len(hits[i]) == 0 {
+			kept = append(kept, p)
+			continue
+		}
+		if clipped, ok := clipPhrase(p, hits[i]); ok {
+			switch {
+			case clipped.Text == "":
+				report.Phrases++
+			case len(clipped.Words) < len(p.Words):
+				report.Clipped++
+				kept = append(kept, clipped)
+			default:
+				kept = append(kept, p)
+			}
+			continue
+		}
+		if covered(i) != "" {
+			report.Phrases++
+			continue
+		}
+		kept = append(kept, p)
+	}
+	return kept, report
+}
+
+// clipPhrase retire de p les mots qui commencent dans l'un des segments (Text
+// vide s'il n'en reste aucun). ok = false si les timings par mot ne suivent
+// pas le texte (voir wordsMatchText) : la phrase ne peut pas être écourtée.
+func clipPhrase(p Phrase, segs []model.Segment) (Phrase, bool) {
+	if len(p.Words) == 0 || !wordsMatchText(p) {
+		return p, false
+	}
+	kept := make([]Word, 0, len(p.Words))
+	for _, w := range p.Words {
+		if !slices.ContainsFunc(segs, func(s model.Segment) bool { return s.Contains(w.StartMs) }) {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == len(p.Words) {
+		return p, true
+	}
+	p.Words = kept
+	if len(kept) == 0 {
+		p.Text = ""
+		return p, true
+	}
+	texts := make([]string, len(kept))
+	for i, w := range kept {
+		texts[i] = strings.TrimSpace(w.Text)
+	}
+	p.Text = strings.Join(texts, " ")
+	p.TimestampMs = kept[0].StartMs
+	if p.EndMs > 0 {
+		p.EndMs = max(kept[len(kept)-1].EndMs, p.TimestampMs)
+	}
+	p.RuneCount = utf8.RuneCountInString(p.Text)
+	p.WordCount = len(kept)
+	return p, true
+}
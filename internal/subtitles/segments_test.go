@@ -0,0 +1,83 @@
+package subtitles
+
+import (
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestApplySegments(t *testing.T) {
+	phrases := []Phrase{
+		{TimestampMs: 0, Text: "intro"},
+		{TimestampMs: 10000, Text: "sponsor read"},
+		{TimestampMs: 15000, Text: "more sponsor"},
+		{TimestampMs: 20000, Text: "content"},
+	}
+	segs := []model.Segment{{Category: "sponsor", StartMs: 10000, EndMs: 20000}}
+
+	t.Run("strip", func(t *testing.T) {
+		out, rep := ApplySegments(phrases, segs, SegmentModeStrip)
+		if len(out) != 2 || out[0].Text != "intro" || out[1].Text != "content" {
+			t.Fatalf("unexpected phrases: %#v", out)
+		}
+		if rep.Phrases != 2 || rep.ByCategory["sponsor"] != 10000 {
+			t.Fatalf("unexpected report: %+v", rep)
+		}
+		if len(phrases) != 4 {
+			t.Fatalf("input must not be modified")
+		}
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		out, rep := ApplySegments(phrases, segs, SegmentModeTag)
+		if len(out) != 4 || rep.Phrases != 2 {
+			t.Fatalf("unexpected result: %#v %+v", out, rep)
+		}
+		if out[1].Segment != "sponsor" || out[3].Segment != "" {
+			t.Fatalf("unexpected tags: %#v", out)
+		}
+		if got := out[1].displayText(); got != "[sponsor] sponsor read" {
+			t.Fatalf("displayText = %q", got)
+		}
+	})
+}
+
+func TestApplySegmentsOverlap(t *testing.T) {
+	segs := []model.Segment{{Category: "sponsor", StartMs: 10_000, EndMs: 20_000}}
+	w := func(ms int64, text string) Word { return Word{Text: text, StartMs: ms, EndMs: ms + 1000} }
+	tests := []struct {
+		name    string
+		phrase  Phrase
+		want    string // "" : phrase retirée
+		clipped int
+	}{
+		{"sans mots, surtout dans le segment", Phrase{TimestampMs: 8_000, EndMs: 18_000, Text: "merci à notre sponsor"}, "", 0},
+		{"sans mots, à peine dans le segment", Phrase{TimestampMs: 2_000, EndMs: 11_000, Text: "on commence"}, "on commence", 0},
+		{
+			"mots qui débordent dans le segment",
+			Phrase{TimestampMs: 8_000, EndMs: 12_000, Text: "voilà. Merci NordVPN", Words: []Word{
+				w(8_000, "voilà."), w(10_500, "Merci"), w(11_000, "NordVPN"),
+			}},
+			"voilà.", 1,
+		},
+		{
+			"mots qui sortent du segment",
+			Phrase{TimestampMs: 19_000, EndMs: 22_000, Text: "lien en description. Revenons", Words: []Word{
+				w(19_000, "lien"), w(19_300, "en"), w(19_600, "description."), w(20_100, "Revenons"),
+			}},
+			"Revenons", 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, rep := ApplySegments([]Phrase{tt.phrase}, segs, SegmentModeStrip)
+			got := ""
+			if len(out) == 1 {
+				got = out[0].Text
+			}
+			if got != tt.want || rep.Clipped != tt.clipped {
+				t.Errorf("got %q (écourtées %d); want %q (%d)", got, rep.Clipped, tt.want, tt.clipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package subtitles
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// SelectReason explique pourquoi une piste a été retenue par SelectTrack.
+type SelectReason string
+
+const (
+	ReasonOriginal  SelectReason = "original"  // langue originale de la vidéo
+	ReasonPreferred SelectReason = "preferred" // langue de preferred_languages
+	ReasonFallback  SelectReason = "fallback"  // aucune correspondance : première piste disponible
+	ReasonTarget    SelectReason = "target"    // déjà dans la langue cible de translate_to
+)
+
+// SelectOptions regroupe les préférences de choix de piste.
+type SelectOptions struct {
+	Preferred   []string // langues essayées après la langue originale
+	TranslateTo string   // langue cible : traduction automatique YouTube si besoin ("" = désactivé)
+}
+
+// TrackSelection : piste retenue + raison du choix (enregistrée dans la note).
+type TrackSelection struct {
+	Track  model.SubtitleTrack
+	Reason SelectReason
+}
+
+func (s TrackSelection) String() string {
+	var why string
+	switch s.Reason {
+	case ReasonOriginal:
+		why = "langue originale"
+	case ReasonPreferred:
+		why = "langue préférée"
+	case ReasonTarget:
+		why = "langue cible"
+	default:
+		why = "aucune langue préférée disponible"
+	}
+	if s.Track.Translated {
+		return fmt.Sprintf("%s (traduction automatique depuis %s, %s)", s.Track.Lang, s.Track.SourceLang, why)
+	}
+	return fmt.Sprintf("%s (%s)", s.Track.Lang, why)
+}
+
+// SelectTrack choisit une piste de façon déterministe :
+//  1. la langue originale `original` (si connue),
+//  2. puis chaque langue de `preferred`, dans l'ordre,
+//  3. sinon la première piste disponible.
+//
+// Pour chaque langue, une correspondance exacte ("en-US") passe avant une
+// correspondance sur la langue de base ("en"). Les pistes sans URL sont ignorées.
+// `tracks` doit être dans un ordre stable (le parser yt les trie par langue).
+func SelectTrack(tracks []model.SubtitleTrack, original string, preferred []string) (TrackSelection, bool) {
+	usable := withURL(tracks)
+	if len(usable) == 0 {
+		return TrackSelection{}, false
+	}
+
+	if t, ok := findLang(usable, original); ok {
+		return TrackSelection{Track: t, Reason: ReasonOriginal}, true
+	}
+	for _, lang := range preferred {
+		if t, ok := findLang(usable, lang); ok {
+			return TrackSelection{Track: t, Reason: ReasonPreferred}, true
+		}
+	}
+	return TrackSelection{Track: usable[0], Reason: ReasonFallback}, true
+}
+
+// withURL retourne les pistes téléchargeables (URL non vide).
+func withURL(tracks []model.SubtitleTrack) []model.SubtitleTrack {
+	out := make([]model.SubtitleTrack, 0, len(tracks))
+	for _, t := range tracks {
+		if t.URL != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// findLang cherche lang dans tracks : exacte d'abord, puis par langue de base.
+func findLang(tracks []model.SubtitleTrack, lang string) (model.SubtitleTrack, bool) {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return model.SubtitleTrack{}, false
+	}
+	for _, t := range tracks {
+		if strings.EqualFold(t.Lang, lang) {
+			return t, true
+		}
+	}
+	base := model.BaseLang(lang)
+	for _, t := range tracks {
+		if model.BaseLang(t.Lang) == base {
+			return t, true
+		}
+	}
+	return model.SubtitleTrack{}, false
+}
+
+// ChooseTrack choisit la piste à utiliser parmi `tracks` (pistes de la vidéo m) :
+//   - avec opts.TranslateTo, une piste déjà dans la langue cible est prise en priorité ;
+//   - sinon SelectTrack (originale > préférées > première) ;
+//   - puis, si la langue cible diffère, la piste est remplacée par sa traduction
+//     automatique (voir Translate).
+func ChooseTrack(m *model.Meta, tracks []model.SubtitleTrack, opts SelectOptions) (TrackSelection, bool) {
+	if m == nil {
+		return TrackSelection{}, false
+	}
+	if t, ok := findLang(withURL(tracks), opts.TranslateTo); ok {
+		return TrackSelection{Track: t, Reason: ReasonTarget}, true
+	}
+	sel, ok := SelectTrack(tracks, m.OriginalLang(), opts.Preferred)
+	if !ok {
+		return TrackSelection{}, false
+	}
+	return Translate(sel, m.TranslatedSubs, opts.TranslateTo), true
+}
+
+// SecondTrack choisit la piste en langue `lang` pour un transcript bilingue :
+// une piste manuelle, puis automatique, déjà dans cette langue, sinon la
+// traduction automatique de la piste principale `primary`.
+// Retourne false si primary est déjà dans la langue demandée.
+func SecondTrack(m *model.Meta, primary TrackSelection, lang string) (TrackSelection, bool) {
+	if m == nil || strings.TrimSpace(lang) == "" || model.BaseLang(primary.Track.Lang) == model.BaseLang(lang) {
+		return TrackSelection{}, false
+	}
+	for _, tracks := range [][]model.SubtitleTrack{m.ManualSubs, m.AutoSubs} {
+		if t, ok := findLang(withURL(tracks), lang); ok {
+			return TrackSelection{Track: t, Reason: ReasonTarget}, true
+		}
+	}
+	if primary.Track.URL == "" {
+		return TrackSelection{}, false
+	}
+	return Translate(primary, m.TranslatedSubs, lang), true
+}
+
+// CounterpartTrack retourne la piste de l'autre source (automatique pour une piste
+// manuelle et inversement) dans la langue de primary, pour comparer les deux (CompareASR).
+// Retourne false pour une piste traduite ou si l'autre source n'a pas cette langue.
+func CounterpartTrack(m *model.Meta, primary TrackSelection) (TrackSelection, bool) {
+	if m == nil || primary.Track.Translated {
+		return TrackSelection{}, false
+	}
+	others := m.AutoSubs
+	if primary.Track.Source == model.SubSourceAutomatic {
+		others = m.ManualSubs
+	}
+	t, ok := findLang(withURL(others), primary.Track.Lang)
+	if !ok || model.BaseLang(t.Lang) != model.BaseLang(primary.Track.Lang) {
+		return TrackSelection{}, false
+	}
+	return TrackSelection{Track: t, Reason: primary.Reason}, true
+}
+
+// Translate retourne la traduction automatique de sel vers target.
+// La piste traduite proposée par yt-dlp (translated) est utilisée si elle existe
+// pour une piste automatique ; sinon l'URL est construite avec le paramètre tlang,
+// ce qui fonctionne aussi pour les pistes manuelles.
+// sel est retourné tel quel si target est vide ou déjà la langue de la piste.
+func Translate(sel TrackSelection, translated []model.SubtitleTrack, target string) TrackSelection {
+	target = strings.TrimSpace(target)
+	if target == "" || model.BaseLang(sel.Track.Lang) == model.BaseLang(target) {
+		return sel
+	}
+	if sel.Track.Source == model.SubSourceAutomatic {
+		if t, ok := findLang(translated, target); ok && t.URL != "" {
+			t.Translated = true
+			t.SourceLang = sel.Track.Lang
+			return TrackSelection{Track: t, Reason: sel.Reason}
+		}
+	}
+	t := sel.Track
+	t.URL = TranslateURL(t.URL, target)
+	t.Lang = target
+	t.Translated = true
+	t.SourceLang = sel.Track.Lang
+	return TrackSelection{Track: t, Reason: sel.Reason}
+}
+
+// TranslateURL ajoute (ou remplace) le paramètre tlang d'une URL timedtext YouTube.
+func TranslateURL(rawURL, lang string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// URL non standard : on ajoute le paramètre tel quel
+		sep := "?"
+		if strings.Contains(rawURL, "?") {
+			sep = "&"
+		}
+		return rawURL + sep + "tlang=" + url.QueryEscape(lang)
+	}
+	q := u.Query()
+	q.Set("tlang", lang)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
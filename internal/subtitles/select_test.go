@@ -0,0 +1,100 @@
+package subtitles
+
+import (
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestSelectTrack(t *testing.T) {
+	tr := func(lang string) model.SubtitleTrack {
+		return model.SubtitleTrack{Lang: lang, Format: model.FormatJSON3, URL: "https://x/" + lang}
+	}
+	tracks := []model.SubtitleTrack{tr("de"), tr("en-US"), tr("fr")}
+
+	tests := []struct {
+		name      string
+		tracks    []model.SubtitleTrack
+		original  string
+		preferred []string
+		wantLang  string
+		wantWhy   SelectReason
+		wantOK    bool
+	}{
+		{"original exacte", tracks, "fr", []string{"en"}, "fr", ReasonOriginal, true},
+		{"original par langue de base", tracks, "en", []string{"fr"}, "en-US", ReasonOriginal, true},
+		{"préférée dans l'ordre", tracks, "ja", []string{"es", "fr", "en"}, "fr", ReasonPreferred, true},
+		{"originale inconnue", tracks, "", []string{"en"}, "en-US", ReasonPreferred, true},
+		{"fallback première piste", tracks, "ja", []string{"es"}, "de", ReasonFallback, true},
+		{"pistes sans URL ignorées", []model.SubtitleTrack{{Lang: "fr"}, tr("en")}, "fr", nil, "en", ReasonFallback, true},
+		{"aucune piste", nil, "fr", []string{"fr"}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SelectTrack(tt.tracks, tt.original, tt.preferred)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got.Track.Lang != tt.wantLang || got.Reason != tt.wantWhy {
+				t.Errorf("got (%s, %s), want (%s, %s)", got.Track.Lang, got.Reason, tt.wantLang, tt.wantWhy)
+			}
+		})
+	}
+}
+
+func TestChooseTrackTranslate(t *testing.T) {
+	orig := model.SubtitleTrack{Lang: "ja", Format: model.FormatJSON3, URL: "https://yt/api/timedtext?v=abc&lang=ja&fmt=json3", Source: model.SubSourceAutomatic}
+	m := &model.Meta{
+		AutoSubs:       []model.SubtitleTrack{orig},
+		TranslatedSubs: []model.SubtitleTrack{{Lang: "de", URL: "https://yt/de", Source: model.SubSourceAutomatic, Translated: true}},
+	}
+
+	// piste traduite fournie par yt-dlp
+	sel, ok := ChooseTrack(m, m.AutoSubs, SelectOptions{TranslateTo: "de"})
+	if !ok || sel.Track.URL != "https://yt/de" || !sel.Track.Translated || sel.Track.SourceLang != "ja" {
+		t.Errorf("de: got %+v", sel)
+	}
+
+	// pas de piste traduite : URL construite avec tlang
+	sel, ok = ChooseTrack(m, m.AutoSubs, SelectOptions{TranslateTo: "en"})
+	if !ok || sel.Track.Lang != "en" || !sel.Track.Translated || sel.Track.SourceLang != "ja" {
+		t.Fatalf("en: got %+v", sel)
+	}
+	if want := "https://yt/api/timedtext?fmt=json3&lang=ja&tlang=en&v=abc"; sel.Track.URL != want {
+		t.Errorf("url = %s, want %s", sel.Track.URL, want)
+	}
+
+	// déjà dans la langue cible : pas de traduction
+	sel, ok = ChooseTrack(m, m.AutoSubs, SelectOptions{TranslateTo: "ja"})
+	if !ok || sel.Track.Translated || sel.Reason != ReasonTarget {
+		t.Errorf("ja: got %+v", sel)
+	}
+}
+
+func TestCounterpartTrack(t *testing.T) {
+	track := func(lang string, src model.SubSource) model.SubtitleTrack {
+		return model.SubtitleTrack{Lang: lang, Format: model.FormatJSON3, URL: "https://x/" + string(src) + "/" + lang, Source: src}
+	}
+	m := &model.Meta{
+		ManualSubs: []model.SubtitleTrack{track("en", model.SubSourceManual), track("de", model.SubSourceManual)},
+		AutoSubs:   []model.SubtitleTrack{track("en-US", model.SubSourceAutomatic), track("fr", model.SubSourceAutomatic)},
+	}
+	tests := []struct {
+		name    string
+		primary model.SubtitleTrack
+		want    string // URL de la piste trouvée, "" si aucune
+	}{
+		{"manuelle -> auto", track("en", model.SubSourceManual), "https://x/automatic/en-US"},
+		{"auto -> manuelle", track("en-US", model.SubSourceAutomatic), "https://x/manual/en"},
+		{"pas de captions dans la langue", track("de", model.SubSourceManual), ""},
+		{"traduction ignorée", model.SubtitleTrack{Lang: "en", Source: model.SubSourceAutomatic, Translated: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CounterpartTrack(m, TrackSelection{Track: tt.primary})
+			if ok != (tt.want != "") || got.Track.URL != tt.want {
+				t.Errorf("got (%q, %v), want %q", got.Track.URL, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package subtitles
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// speakers.go : changements de locuteur marqués dans les sous-titres :
+//   - tiret de dialogue en début de réplique ("- Bonjour. - Salut.") ;
+//   - chevrons ">>" (sous-titres de télévision, captions auto) ;
+//   - étiquette "NOM:" ou "Marie Curie:" en début de réplique.
+// Tirets et chevrons ne disent que "quelqu'un d'autre parle" : les locuteurs
+// anonymes alternent entre S1 et S2 (interview, podcast à deux voix). Les
+// étiquettes sont gardées telles quelles et peuvent être renommées (WithSpeakerNames).
+
+const (
+	anonSpeaker1 = "S1"
+	anonSpeaker2 = "S2"
+	// une étiquette "NOM:" fait au plus maxSpeakerLabelWords mots et maxSpeakerLabelRunes runes
+	maxSpeakerLabelWords = 3
+	maxSpeakerLabelRunes = 32
+)
+
+// voiceMark : marque d'une balise de voix WebVTT dans le texte d'une cue
+// ("<v Bob>" -> voiceMark + "Bob: ", voir cleanCueText), résolue par voiceTurns.
+const voiceMark = "\x1e"
+
+var reVoiceMark = regexp.MustCompile(voiceMark + `([^` + voiceMark + `:]*):\s*`)
+
+// voiceTurns : les marques de voix deviennent des changements de locuteur
+// ">> Nom:" si speakers, et disparaissent sinon (comme les autres balises).
+func voiceTurns(s string, speakers bool) string {
+	if !strings.Contains(s, voiceMark) {
+		return s
+	}
+	if speakers {
+		return normalizeWhitespace(reVoiceMark.ReplaceAllString(s, ">> $1: "))
+	}
+	return normalizeWhitespace(reVoiceMark.ReplaceAllString(s, ""))
+}
+
+// speakerTurn : morceau du texte d'un event prononcé par un même locuteur.
+type speakerTurn struct {
+	marked    bool   // la réplique commence par un marqueur (tiret, >>, étiquette)
+	label     string // étiquette "NOM:" ("" : locuteur anonyme)
+	text      string // texte sans le marqueur
+	startRune int    // position (en runes) de text dans le texte de l'event, marqueurs exclus
+}
+
+// turnToken : un mot du texte et sa position.
+type turnToken struct {
+	text       string
+	start, end int // octets
+	runeStart  int
+}
+
+func turnTokens(s string) []turnToken {
+	var out []turnToken
+	start, runeStart, runes := -1, 0, 0
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				out = append(out, turnToken{text: s[start:i], start: start, end: i, runeStart: runeStart})
+				start = -1
+			}
+		} else if start < 0 {
+			start, runeStart = i, runes
+		}
+		runes++
+	}
+	if start >= 0 {
+		out = append(out, turnToken{text: s[start:], start: start, end: len(s), runeStart: runeStart})
+	}
+	return out
+}
+
+// mayHaveTurns : s contient un marqueur possible (évite de découper en mots
+// les events sans tiret, chevrons ni deux-points, le cas courant).
+func mayHaveTurns(s string) bool {
+	return strings.ContainsAny(s, ":-–—") || strings.Contains(s, ">>")
+}
+
+// isDialogueDash : tiret isolé ("-", "–", "—").
+func isDialogueDash(s string) bool {
+	return s == "-" || s == "–" || s == "—"
+}
+
+// endsSentence : le mot se termine par une ponctuation finale (closers ignorés).
+func endsSentence(word string) bool {
+	r, ok := lastNonSpaceRune(trimTrailingClosers(word))
+	return ok && isSentenceTerminatorRune(r)
+}
+
+// turnMarkerAt : tokens[i] ouvre une réplique (">>", ou tiret en début de texte
+// ou après une fin de phrase). Retourne le nombre de tokens du marqueur.
+func turnMarkerAt(tokens []turnToken, i int) int {
+	t := tokens[i].text
+	if t == ">>" {
+		return 1
+	}
+	if isDialogueDash(t) && (i == 0 || endsSentence(tokens[i-1].text)) {
+		return 1
+	}
+	return 0
+}
+
+// labelAt : étiquette candidate "NOM:" commençant à tokens[i] et son nombre de
+// tokens (0 si aucune). Chaque mot commence par une majuscule, le dernier finit par ':'.
+func labelAt(tokens []turnToken, i int) (string, int) {
+	for n := 1; n <= maxSpeakerLabelWords && i+n <= len(tokens); n++ {
+		w := tokens[i+n-1].text
+		body, colon := strings.CutSuffix(w, ":")
+		if body == "" || !startsUpper(body) || strings.ContainsFunc(body, isSentenceTerminatorRune) {
+			return "", 0
+		}
+		if colon {
+			label := strings.TrimSuffix(joinTokens(tokens[i:i+n]), ":")
+			if utf8.RuneCountInString(label) > maxSpeakerLabelRunes {
+				return "", 0
+			}
+			return label, n
+		}
+	}
+	return "", 0
+}
+
+func joinTokens(tokens []turnToken) string {
+	parts := make([]string, len(tokens))
+	for i, t := range tokens {
+		parts[i] = t.text
+	}
+	return strings.Join(parts, " ")
+}
+
+// speakerLabels relève les étiquettes "NOM:" en début de réplique. Une étiquette
+// annoncée par ">>" (ou une balise de voix WebVTT) est retenue d'office ; les
+// autres, en capitales ou non, doivent revenir au moins deux fois pour ne pas
+// confondre "Remarque: ..." ou "NOTE: ..." avec un locuteur. Chaque étiquette
+// est jugée seule.
+func speakerLabels(texts []string) map[string]struct{} {
+	counts := make(map[string]int)
+	explicit := make(map[string]bool)
+	for _, s := range texts {
+		if !strings.Contains(s, ":") {
+			continue
+		}
+		tokens := turnTokens(s)
+		for i := 0; i < len(tokens); i++ {
+			if i > 0 && turnMarkerAt(tokens, i-1) == 0 && !endsSentence(tokens[i-1].text) {
+				continue
+			}
+			if label, n := labelAt(tokens, i); n > 0 {
+				counts[label]++
+				if i > 0 && tokens[i-1].text == ">>" {
+					explicit[label] = true
+				}
+				i += n - 1
+			}
+		}
+	}
+	labels := make(map[string]struct{})
+	for label, n := range counts {
+		if n >= 2 || explicit[label] {
+			labels[label] = struct{}{}
+		}
+	}
+	return labels
+}
+
+// splitTurns découpe le texte d'un event en répliques. labels : étiquettes retenues
+// (speakerLabels). Le texte précédant le premier marqueur forme une réplique non marquée.
+// spoken : nombre de runes du texte sans les marqueurs, pour répartir la durée de l'event.
+func splitTurns(s string, labels map[string]struct{}) (turns []speakerTurn, spoken int) {
+	if !mayHaveTurns(s) {
+		return []speakerTurn{{text: s}}, utf8.RuneCountInString(s)
+	}
+	tokens := turnTokens(s)
+	var out []speakerTurn
+	cur := speakerTurn{}
+	first := -1 // premier token du texte de la réplique courante
+	last := -1
+	skipped := 0 // runes des marqueurs déjà retirés (espace suivant compris)
+	skip := func(from, to int) {
+		for _, t := range tokens[from:to] {
+			skipped += utf8.RuneCountInString(t.text) + 1
+		}
+	}
+	flush := func() {
+		if first >= 0 {
+			cur.text = s[tokens[first].start:tokens[last].end]
+			cur.startRune = tokens[first].runeStart - skipped
+		}
+		if cur.marked || cur.text != "" {
+			out = append(out, cur)
+		}
+		cur = speakerTurn{}
+		first, last = -1, -1
+	}
+	for i := 0; i < len(tokens); i++ {
+		atStart := i == 0 || endsSentence(tokens[i-1].text)
+		if n := turnMarkerAt(tokens, i); n > 0 {
+			flush()
+			cur.marked = true
+			skip(i, i+n)
+			i += n
+			if i < len(tokens) {
+				if label, m := labelAt(tokens, i); m > 0 {
+					if _, ok := labels[label]; ok {
+						cur.label = label
+						skip(i, i+m)
+						i += m
+					}
+				}
+			}
+			i-- // le for avance
+			continue
+		}
+		if atStart {
+			if label, m := labelAt(tokens, i); m > 0 {
+				if _, ok := labels[label]; ok {
+					flush()
+					cur.marked = true
+					cur.label = label
+					skip(i, i+m)
+					i += m - 1
+					continue
+				}
+			}
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	flush()
+	return out, max(utf8.RuneCountInString(s)-skipped, 0)
+}
+
+// nextSpeaker : locuteur d'une nouvelle réplique ; sans étiquette, alterne S1/S2.
+func nextSpeaker(curr, label string) string {
+	if label != "" {
+		return label
+	}
+	if curr == anonSpeaker1 {
+		return anonSpeaker2
+	}
+	return anonSpeaker1
+}
+
+// dropLoneSpeaker efface les locuteurs quand moins de deux répliques ont été
+// marquées (un tiret isolé n'est pas un dialogue).
+func dropLoneSpeaker(phrases []Phrase, turns int) {
+	if turns >= 2 {
+		return
+	}
+	for i := range phrases {
+		phrases[i].Speaker = ""
+	}
+}
+
+// HasSpeakers : au moins une phrase est attribuée à un locuteur.
+func (t Transcript) HasSpeakers() bool {
+	for _, p := range t.Phrases {
+		if p.Speaker != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Speakers retourne les locuteurs dans leur ordre d'apparition.
+func (t Transcript) Speakers() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, p := range t.Phrases {
+		if p.Speaker != "" && !seen[p.Speaker] {
+			seen[p.Speaker] = true
+			out = append(out, p.Speaker)
+		}
+	}
+	return out
+}
+
+// WithSpeakerNames retourne une copie du transcript où les étiquettes de locuteur
+// sont remplacées par les noms donnés (clés insensibles à la casse : "s1", "JOHN").
+func (t Transcript) WithSpeakerNames(names map[string]string) Transcript {
+	if len(names) == 0 {
+		return t
+	}
+	byKey := make(map[string]string, len(names))
+	for k, v := range names {
+		if k, v = strings.TrimSpace(k), strings.TrimSpace(v); k != "" && v != "" {
+			byKey[strings.ToLower(k)] = v
+		}
+	}
+	return t.mapSpeakers(func(s string) string {
+		if name, ok := byKey[strings.ToLower(s)]; ok {
+			return name
+		}
+		return s
+	})
+}
+
+// mapSpeakers : copie du transcript, locuteurs transformés par f (Phrases est copiée).
+func (t Transcript) mapSpeakers(f func(string) string) Transcript {
+	phrases := make([]Phrase, len(t.Phrases))
+	for i, p := range t.Phrases {
+		p.Speaker = f(p.Speaker)
+		phrases[i] = p
+	}
+	t.Phrases = phrases
+	return t
+}
+
+// Dialogue retourne le transcript en répliques : un paragraphe par changement de
+// locuteur, préfixé de son nom ("S1 : ..."), chapitres insérés en titres.
+func (t Transcript) Dialogue() string {
+	if len(t.Phrases) == 0 {
+		return ""
+	}
+	return renderParagraphs(t.transcriptEvents(t.ChapterSnap), ParagraphOptions{})
+}
@@ -0,0 +1,177 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestManualPhrasesSpeakers(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []string
+		want   []string // "locuteur|texte"
+	}{
+		{
+			name:   "tirets de dialogue",
+			events: []string{"- Tu viens ? - Non, je reste.", "- Dommage."},
+			want:   []string{"S1|Tu viens ?", "S2|Non, je reste.", "S1|Dommage."},
+		},
+		{
+			name:   "chevrons",
+			events: []string{">> Welcome to the show.", ">> Thanks for having me.", "It's great to be here."},
+			want:   []string{"S1|Welcome to the show.", "S2|Thanks for having me.", "S2|It's great to be here."},
+		},
+		{
+			name:   "étiquettes en capitales",
+			events: []string{"HOST: So what happened", "next? GUEST: We shipped it.", "HOST: Great.", "GUEST: Thanks."},
+			want:   []string{"HOST|So what happened next?", "GUEST|We shipped it.", "HOST|Great.", "GUEST|Thanks."},
+		},
+		{
+			name:   "étiquette répétée",
+			events: []string{"Marie: Bonjour.", "Paul: Salut.", "Marie: Ça va ?", "Paul: Oui."},
+			want:   []string{"Marie|Bonjour.", "Paul|Salut.", "Marie|Ça va ?", "Paul|Oui."},
+		},
+		{
+			name:   "une étiquette retenue ne promeut pas les autres",
+			events: []string{"HOST: Bonjour.", "Remarque: ceci est important.", "GUEST: Merci.", "HOST: Au revoir.", "GUEST: Salut."},
+			want:   []string{"HOST|Bonjour.", "HOST|Remarque: ceci est important.", "GUEST|Merci.", "HOST|Au revoir.", "GUEST|Salut."},
+		},
+		{
+			name:   "étiquette en capitales isolée : pas un locuteur",
+			events: []string{"- Tu viens ? - Non.", "NOTE: fin de la scène.", "PS: à suivre."},
+			want:   []string{"S1|Tu viens ?", "S2|Non.", "S2|NOTE: fin de la scène.", "S2|PS: à suivre."},
+		},
+		{
+			name:   "étiquette en capitales annoncée par >>",
+			events: []string{">> JOHN: Hello there.", ">> MARY: Hi.", "Nice day."},
+			want:   []string{"JOHN|Hello there.", "MARY|Hi.", "MARY|Nice day."},
+		},
+		{
+			name:   "étiquette isolée : pas un locuteur",
+			events: []string{"Remarque: ceci est important.", "Ensuite on continue."},
+			want:   []string{"|Remarque: ceci est important.", "|Ensuite on continue."},
+		},
+		{
+			name:   "tiret isolé : pas un dialogue",
+			events: []string{"- Bonjour à tous.", "On commence."},
+			want:   []string{"|Bonjour à tous.", "|On commence."},
+		},
+		{
+			name:   "trait d'union dans la phrase",
+			events: []string{"C'est - disons - compliqué."},
+			want:   []string{"|C'est - disons - compliqué."},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var raw rawJSON3
+			for i, text := range tc.events {
+				raw.Events = append(raw.Events, rawEvent{
+					TStartMs:    ptrInt64(int64(i) * 2000),
+					DDurationMs: ptrInt64(2000),
+					Segs:        []rawSeg{{Utf8: text}},
+				})
+			}
+			phrases, err := TransformManualRawToPhrases(raw, Options{Speakers: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range phrases {
+				got = append(got, p.Speaker+"|"+p.Text)
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSpeakersOff(t *testing.T) {
+	// speakers.enabled: false : le texte garde ses marqueurs, sans locuteur
+	raw := rawJSON3{Events: []rawEvent{{
+		TStartMs: ptrInt64(0), DDurationMs: ptrInt64(2000),
+		Segs: []rawSeg{{Utf8: "- Tu viens ? - Non."}},
+	}}}
+	phrases, err := TransformManualRawToPhrases(raw, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phrases) != 2 || phrases[0].Text != "- Tu viens ?" || phrases[0].Speaker != "" {
+		t.Errorf("got %+v", phrases)
+	}
+
+	// balise de voix WebVTT : locuteur si speakers, retirée sinon
+	vtt := "WEBVTT\n\n00:01.000 --> 00:03.000\n<v Bob>Hello.</v>\n\n00:04.000 --> 00:05.000\n<v Ann>Hi.\n\n00:06.000 --> 00:07.000\n<v Bob>Bye.\n"
+	raw, err = ParseBytes([]byte(vtt), model.FormatVTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, speakers := range []bool{false, true} {
+		phrases, err := TransformManualRawToPhrases(raw, Options{Speakers: speakers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range phrases {
+			got = append(got, p.Speaker+"|"+p.Text)
+		}
+		want := "|Hello. |Hi. |Bye."
+		if speakers {
+			want = "Bob|Hello. Ann|Hi. Bob|Bye."
+		}
+		if strings.Join(got, " ") != want {
+			t.Errorf("speakers=%v : got %q; want %q", speakers, got, want)
+		}
+	}
+}
+
+func TestAutoPhrasesSpeakers(t *testing.T) {
+	// captions auto : ">>" en tête de seg ouvre une réplique, locuteurs alternés
+	raw := rawJSON3{Events: []rawEvent{
+		{TStartMs: ptrInt64(0), DDurationMs: ptrInt64(3000), Segs: []rawSeg{
+			{Utf8: ">> welcome"}, {Utf8: " back", TOffsetMs: ptrInt64(400)},
+		}},
+		{TStartMs: ptrInt64(1000), DDurationMs: ptrInt64(3000), Segs: []rawSeg{
+			{Utf8: ">>"}, {Utf8: " thanks", TOffsetMs: ptrInt64(200)}, {Utf8: " >> so", TOffsetMs: ptrInt64(900)},
+		}},
+	}}
+	tests := []struct {
+		speakers bool
+		want     string
+	}{
+		{true, "S1|welcome back S2|thanks S1|so"},
+		{false, "|>> welcome back >> thanks >> so"},
+	}
+	for _, tt := range tests {
+		phrases, err := TransformAutoRawToPhrases(raw, Options{Speakers: tt.speakers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range phrases {
+			got = append(got, p.Speaker+"|"+p.Text)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("speakers=%v : got %q; want %q", tt.speakers, got, tt.want)
+		}
+	}
+}
+
+func TestDialogueWithSpeakerNames(t *testing.T) {
+	tr := Transcript{Phrases: []Phrase{
+		{TimestampMs: 0, Text: "Welcome.", Speaker: "S1"},
+		{TimestampMs: 1000, Text: "Today we talk about Go.", Speaker: "S1"},
+		{TimestampMs: 2000, Text: "Thanks.", Speaker: "S2"},
+	}}
+	got := tr.WithSpeakerNames(map[string]string{"s1": "Alice", "S2": "Bob"}).Dialogue()
+	want := "Alice : Welcome. Today we talk about Go.\n\nBob : Thanks.\n"
+	if got != want {
+		t.Errorf("Dialogue() = %q; want %q", got, want)
+	}
+	if tr.Phrases[0].Speaker != "S1" {
+		t.Errorf("WithSpeakerNames a modifié l'original")
+	}
+}
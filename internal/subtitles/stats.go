@@ -0,0 +1,192 @@
+package subtitles
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// stats.go : statistiques d'un transcript (Transcript.Stats).
+//   - débit : mots par minute de parole, les trous (silence, musique, passage
+//     non sous-titré) de plus de GapMs n'étant pas comptés comme parole ;
+//   - termes : mots de 3 lettres ou plus hors mots vides, élisions et "'s"
+//     retirés ("l'architecture" -> "architecture"). Pas de termes en japonais,
+//     chinois... faute de découpage en mots.
+
+// minTermRunes : longueur minimale d'un terme.
+const minTermRunes = 3
+
+// StatsOptions : paramètres de Transcript.Stats.
+type StatsOptions struct {
+	GapMs     int64    // trous signalés au-delà de cette durée (0 = aucun)
+	TopTerms  int      // nombre de termes les plus fréquents retenus
+	StopWords []string // mots vides en plus des listes intégrées
+}
+
+// mots vides par langue (articles, pronoms, auxiliaires, mots de liaison)
+var builtinStopWords = map[string][]string{
+	"en": {"the", "and", "for", "are", "but", "not", "you", "all", "any", "can", "had", "her", "was",
+		"one", "our", "out", "has", "him", "his", "how", "its", "let", "may", "now", "see", "she",
+		"too", "use", "way", "who", "did", "get", "got", "yes", "yeah", "okay", "that", "this",
+		"with", "have", "from", "they", "will", "would", "there", "their", "what", "about", "which",
+		"when", "make", "like", "just", "know", "some", "could", "them", "than", "then", "these",
+		"those", "into", "your", "been", "were", "more", "also", "very", "really", "going", "want",
+		"because", "here", "thing", "things", "think", "actually", "something", "right", "well",
+		"much", "even", "only", "over", "such", "being", "does", "doing", "should", "where", "while",
+		"other", "lot", "gonna", "kind", "say", "said", "come", "back", "need", "take", "look",
+		"don't", "can't", "didn't", "doesn't", "isn't", "aren't", "wasn't", "won't", "you're", "they're"},
+	"fr": {"les", "des", "une", "est", "que", "qui", "pas", "pour", "dans", "par", "sur", "avec",
+		"plus", "mais", "son", "ses", "aux", "elle", "ils", "elles", "nous", "vous", "leur", "leurs",
+		"cette", "ces", "ont", "été", "être", "avoir", "fait", "faire", "comme", "tout", "tous",
+		"toute", "toutes", "aussi", "bien", "très", "alors", "donc", "car", "quand", "où", "encore",
+		"même", "sont", "était", "avait", "peut", "peu", "ici", "là", "voilà", "ça", "cela", "ceci",
+		"moi", "toi", "lui", "mon", "ton", "mes", "tes", "notre", "votre", "nos", "vos", "quoi",
+		"parce", "après", "avant", "entre", "sans", "sous", "chez", "vers", "oui", "non", "bon",
+		"enfin", "juste", "vraiment", "chose", "choses", "dire", "dit", "va", "vais", "vas",
+		"allez", "aller", "faut", "veux", "voir", "coup", "genre", "truc", "trucs", "quelque"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "einen", "dem", "den", "des",
+		"mit", "sich", "auf", "für", "von", "auch", "aber", "wie", "wir", "ihr", "sie", "was", "wenn",
+		"noch", "nur", "dann", "schon", "mal", "also", "hat", "haben", "sind", "war", "wird", "kann",
+		"können", "ich", "mir", "mich", "dir", "dich", "uns", "euch", "man", "hier", "jetzt", "sehr",
+		"oder", "doch", "ganz", "immer", "einfach", "gibt", "eben", "halt", "genau", "dass", "ja"},
+	"es": {"que", "los", "las", "una", "por", "con", "para", "del", "como", "más", "pero", "sus",
+		"este", "esta", "esto", "estos", "ese", "esa", "eso", "hay", "muy", "también", "fue", "ser",
+		"son", "está", "están", "tiene", "todo", "todos", "cuando", "donde", "porque", "bien", "así",
+		"nos", "les", "entonces", "bueno", "vamos", "algo", "puede", "hacer", "ahora", "aquí", "sí"},
+}
+
+// Stats calcule les statistiques du transcript ; VideoID et Keywords sont à
+// renseigner par l'appelant.
+func (t Transcript) Stats(opts StatsOptions) model.TranscriptStats {
+	s := model.TranscriptStats{Title: t.Title, Lang: t.Track.Lang}
+	if len(t.Phrases) == 0 {
+		return s
+	}
+	ends := phraseEnds(t.Phrases)
+	words := make([]int, len(t.Phrases))
+	var prevEnd int64
+	for i, p := range t.Phrases {
+		words[i] = p.WordCount
+		if words[i] == 0 {
+			words[i] = CountWords(p.Text, t.Track.Lang)
+		}
+		s.Words += words[i]
+		if opts.GapMs > 0 && p.TimestampMs-prevEnd > opts.GapMs {
+			s.Gaps = append(s.Gaps, model.Gap{StartMs: prevEnd, EndMs: p.TimestampMs})
+		}
+		prevEnd = max(prevEnd, ends[i])
+	}
+	s.DurationMs = prevEnd
+	s.SpeechMs = s.DurationMs - s.GapsMs()
+	s.WPM = model.WordsPerMinute(s.Words, s.SpeechMs)
+	s.Chapters = chapterStats(t, words, s.Gaps, s.DurationMs)
+
+	if !IsSpacelessLang(t.Track.Lang) {
+		s.Terms = termCounts(t.Phrases, t.Track.Lang, opts.StopWords)
+		s.TopTerms = TopTerms(s.Terms, opts.TopTerms)
+	}
+	return s
+}
+
+// chapterStats : mots et débit de chaque chapitre, trous exclus.
+func chapterStats(t Transcript, words []int, gaps []model.Gap, endMs int64) []model.ChapterStats {
+	if len(t.Chapters) == 0 {
+		return nil
+	}
+	out := make([]model.ChapterStats, len(t.Chapters))
+	bounds := make([]int64, len(t.Chapters)+1)
+	for i, c := range t.Chapters {
+		out[i] = model.ChapterStats{Title: c.Title, StartMs: int64(c.Start) * 1000}
+		bounds[i] = out[i].StartMs
+	}
+	bounds[len(t.Chapters)] = max(endMs, bounds[len(t.Chapters)-1])
+	for i, p := range t.Phrases {
+		// dernier chapitre commencé avant la phrase (le premier si elle le précède)
+		c, _ := slices.BinarySearch(bounds[:len(t.Chapters)], p.TimestampMs+1)
+		out[max(c-1, 0)].Words += words[i]
+	}
+	for i := range out {
+		start, end := bounds[i], bounds[i+1]
+		speech := end - start
+		for _, g := range gaps {
+			speech -= max(0, min(end, g.EndMs)-max(start, g.StartMs))
+		}
+		out[i].WPM = model.WordsPerMinute(out[i].Words, speech)
+	}
+	return out
+}
+
+// termCounts : occurrences de chaque terme, mots vides exclus.
+func termCounts(phrases []Phrase, lang string, extra []string) map[string]int {
+	stop := stopWordSet(lang, extra)
+	counts := map[string]int{}
+	for _, p := range phrases {
+		for _, term := range phraseTerms(p.Text, stop) {
+			counts[term]++
+		}
+	}
+	return counts
+}
+
+// stopWordSet : mots vides intégrés de la langue, mots de remplissage et extra.
+func stopWordSet(lang string, extra []string) map[string]struct{} {
+	stop := lexicon(builtinStopWords, lang)
+	for _, table := range []map[string][]string{builtinFillers, nonFinalWords} {
+		for w := range lexicon(table, lang) {
+			stop[w] = struct{}{}
+		}
+	}
+	for _, w := range extra {
+		stop[strings.ToLower(strings.TrimSpace(w))] = struct{}{}
+	}
+	return stop
+}
+
+// phraseTerms : termes du texte (voir termKey), mots vides et mots courts exclus.
+func phraseTerms(text string, stop map[string]struct{}) []string {
+	var terms []string
+	for _, f := range strings.Fields(text) {
+		term := termKey(f)
+		if utf8.RuneCountInString(term) < minTermRunes || !strings.ContainsFunc(term, unicode.IsLetter) {
+			continue
+		}
+		if _, ok := stop[term]; ok {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// termKey : forme comparable d'un mot (termForm en minuscules).
+func termKey(word string) string {
+	return strings.ToLower(termForm(word))
+}
+
+// termForm : le mot sans ponctuation autour, sans élision ("l'", "qu'") ni "'s" final.
+func termForm(word string) string {
+	w := bareWordRaw(strings.ReplaceAll(word, "’", "'"))
+	if before, after, ok := strings.Cut(w, "'"); ok && (utf8.RuneCountInString(before) <= 2 || strings.HasSuffix(strings.ToLower(before), "qu")) {
+		w = after
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(w, "'s"), "'S")
+}
+
+// TopTerms : les n termes les plus fréquents (à égalité, ordre alphabétique).
+func TopTerms(counts map[string]int, n int) []model.TermCount {
+	if n <= 0 || len(counts) == 0 {
+		return nil
+	}
+	all := make([]model.TermCount, 0, len(counts))
+	for term, c := range counts {
+		all = append(all, model.TermCount{Term: term, Count: c})
+	}
+	slices.SortFunc(all, func(a, b model.TermCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Term, b.Term))
+	})
+	return all[:min(n, len(all))]
+}
@@ -0,0 +1,39 @@
+package subtitles
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func TestTranscriptStats(t *testing.T) {
+	tr := Transcript{
+		Track: model.SubtitleTrack{Lang: "fr"},
+		Phrases: []Phrase{
+			{TimestampMs: 0, EndMs: 30_000, Text: "Kubernetes orchestre les conteneurs de l'application."},
+			{TimestampMs: 30_000, EndMs: 60_000, Text: "Les conteneurs Kubernetes sont légers."},
+			// 60 s de musique
+			{TimestampMs: 120_000, EndMs: 180_000, Text: "Qu'est-ce qu'un pod ? Un groupe de conteneurs."},
+		},
+		Chapters: []model.Chapter{{Start: 0, Title: "Intro"}, {Start: 90, Title: "Pods"}},
+	}
+	s := tr.Stats(StatsOptions{GapMs: 10_000, TopTerms: 2})
+
+	got := fmt.Sprintf("%d mots, %d ms, parole %d ms, %.1f mpm, trous %v", s.Words, s.DurationMs, s.SpeechMs, s.WPM, s.Gaps)
+	want := "19 mots, 180000 ms, parole 120000 ms, 9.5 mpm, trous [{60000 120000}]"
+	if got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	chapters := fmt.Sprintf("%v", s.Chapters)
+	if want := "[{Intro 0 11 11} {Pods 90000 8 8}]"; chapters != want {
+		t.Errorf("chapitres = %s; want %s", chapters, want)
+	}
+	top := fmt.Sprintf("%v", s.TopTerms)
+	if want := "[{conteneurs 3} {kubernetes 2}]"; top != want {
+		t.Errorf("termes = %s; want %s", top, want)
+	}
+	if s.Terms["application"] != 1 || s.Terms["pod"] != 1 || s.Terms["les"] != 0 {
+		t.Errorf("élisions ou mots vides mal traités : %v", s.Terms)
+	}
+}
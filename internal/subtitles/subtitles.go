@@ -1,8 +1,10 @@
 package subtitles
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/patrickprogramme/subscribe/internal/fetch"
@@ -10,9 +12,11 @@ import (
 )
 
 // NewSubtitleDownloadFromMeta : constructeur pur.
-// Retourne un SubtitleDownload avec Title et Track remplis, Data == nil.
+// Retourne un SubtitleDownload avec Title, Track et Reason remplis, Data == nil.
+// La piste est choisie par ChooseTrack : langue originale, puis opts.Preferred, puis la première,
+// traduite automatiquement si opts.TranslateTo le demande.
 // convention? : le préfixe New implique pas d’effets de bord.
-func NewSubtitleDownloadFromMeta(m *model.Meta, ss model.SubSource) (SubtitleDownload, bool) {
+func NewSubtitleDownloadFromMeta(m *model.Meta, ss model.SubSource, opts SelectOptions) (SubtitleDownload, bool) {
 	if m == nil {
 		return SubtitleDownload{}, false
 	}
@@ -26,18 +30,16 @@ func NewSubtitleDownloadFromMeta(m *model.Meta, ss model.SubSource) (SubtitleDow
 		tracks = m.AutoSubs // fallback
 	}
 
-	for _, t := range tracks {
-		if t.URL == "" {
-			continue
-		}
-		title := tTitleOrID(m)
-		return SubtitleDownload{
-			Title: title,
-			Track: t,
-			Data:  nil,
-		}, true
+	sel, ok := ChooseTrack(m, tracks, opts)
+	if !ok {
+		return SubtitleDownload{}, false
 	}
-	return SubtitleDownload{}, false
+	return SubtitleDownload{
+		Title:  tTitleOrID(m),
+		Track:  sel.Track,
+		Reason: sel.Reason,
+		Data:   nil,
+	}, true
 }
 
 // tTitleOrID retourne le titre, ou sinon l'ID de la vidéo
@@ -55,32 +57,63 @@ func tTitleOrID(m *model.Meta) string {
 // le SubtitleDownload avec Data rempli. Nom explicite => fait du réseau.
 //
 // - ctx : contexte (annulation/timeout). Peut être nil.
-// - timeout, maxBytes : paramètres pour fetch.FetchBytesWithTimeout.
+// - opts : langues préférées et traduction, voir ChooseTrack.
+// - timeout, maxBytes, keepData : voir DownloadTrack.
 // Retourne ErrNoSubtitle si aucune piste trouvée pour la source demandée.
-func DownloadSubtitleFromMeta(ctx context.Context, m *model.Meta, ss model.SubSource, timeout time.Duration, maxBytes int64) (SubtitleDownload, error) {
+func DownloadSubtitleFromMeta(ctx context.Context, m *model.Meta, ss model.SubSource, opts SelectOptions, timeout time.Duration, maxBytes int64, keepData bool) (SubtitleDownload, error) {
 	// constructeur pur
-	sd, ok := NewSubtitleDownloadFromMeta(m, ss)
+	sd, ok := NewSubtitleDownloadFromMeta(m, ss, opts)
 	if !ok {
 		return SubtitleDownload{}, ErrNoSubtitle
 	}
 
-	// downloader (utilise internal/fetch helper)
-	data, err := fetch.FetchBytesWithTimeout(ctx, sd.Track.URL, timeout, maxBytes)
+	return DownloadTrack(ctx, sd, timeout, maxBytes, keepData)
+}
+
+// DownloadTrack télécharge sd.Track.URL (au plus maxBytes octets).
+// Une piste json3 est décodée au fil du corps HTTP (ParseJSON3Reader) sans être
+// gardée en mémoire : ParseRaw retourne alors les events décodés et Data reste nil,
+// sauf si keepData (save_raw_subs) demande aussi les octets bruts.
+// Les autres formats sont lus d'un bloc dans Data.
+func DownloadTrack(ctx context.Context, sd SubtitleDownload, timeout time.Duration, maxBytes int64, keepData bool) (SubtitleDownload, error) {
+	if sd.Track.Format != "" && sd.Track.Format != model.FormatJSON3 {
+		data, err := fetch.FetchBytesWithTimeout(ctx, sd.Track.URL, timeout, maxBytes)
+		if err != nil {
+			return SubtitleDownload{}, fmt.Errorf("download subtitle: %w", err)
+		}
+		sd.Data = data
+		return sd, nil
+	}
+
+	body, err := fetch.Open(ctx, sd.Track.URL, timeout, maxBytes)
+	if err != nil {
+		return SubtitleDownload{}, fmt.Errorf("download subtitle: %w", err)
+	}
+	defer body.Close()
+	var r io.Reader = body
+	var buf bytes.Buffer
+	if keepData {
+		r = io.TeeReader(body, &buf)
+	}
+	raw, err := ParseJSON3Reader(r)
 	if err != nil {
 		return SubtitleDownload{}, fmt.Errorf("download subtitle: %w", err)
 	}
-	sd.Data = data
+	sd.parsed = &raw
+	if keepData {
+		sd.Data = buf.Bytes()
+	}
 	return sd, nil
 }
 
 // TransformRawToPhrases choisit la stratégie selon la source.
-func TransformRawToPhrases(raw rawJSON3, src model.SubSource) ([]Phrase, error) {
+func TransformRawToPhrases(raw rawJSON3, src model.SubSource, opts Options) ([]Phrase, error) {
 	switch src {
 	case model.SubSourceManual:
-		return TransformManualRawToPhrases(raw)
+		return TransformManualRawToPhrases(raw, opts)
 	case model.SubSourceAutomatic:
-		return TransformAutoRawToPhrases(raw)
+		return TransformAutoRawToPhrases(raw, opts)
 	default:
-		return TransformAutoRawToPhrases(raw) // choix par défaut
+		return TransformAutoRawToPhrases(raw, opts) // choix par défaut
 	}
 }
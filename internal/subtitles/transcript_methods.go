@@ -11,7 +11,7 @@ import (
 )
 
 // Plain retourne le transcript au format lisible (une phrase par ligne).
-// Si des chapitres existent, ils sont insérés via TranscriptWithChaptersSplit(0, asPlain).
+// Si des chapitres existent, ils sont insérés via transcriptWithChaptersSplit, recalés selon ChapterSnap.
 func (t Transcript) Plain() string {
 	if len(t.Phrases) == 0 {
 		return ""
@@ -20,16 +20,17 @@ func (t Transcript) Plain() string {
 		// pas de chapitres
 		return t.PlainNoChapters()
 	}
-	return t.transcriptWithChaptersSplit(0, asPlain)
+	return t.transcriptWithChaptersSplit(t.ChapterSnap, asPlain)
 }
 
-// PlainNoChapters retourne le transcript sous forme lisible : une phrase par ligne.
+// PlainNoChapters retourne le transcript sous forme lisible : une phrase par ligne,
+// sans chapitres ni notice de traduction (le txt de Render peut en avoir).
 // Utile pour sauvegarde .txt simple.
 func (t Transcript) PlainNoChapters() string {
 	var b strings.Builder
 	for i, p := range t.Phrases {
 		// on écrit la phrase telle quelle (déjà normalisée)
-		b.WriteString(p.Text)
+		b.WriteString(p.displayText())
 		// fin de ligne sauf si c'est la dernière phrase (on ajoute tout de même un newline final)
 		if i < len(t.Phrases)-1 {
 			b.WriteString("\n")
@@ -40,7 +41,7 @@ func (t Transcript) PlainNoChapters() string {
 }
 
 // Collapsed retourne le transcript en un seul paragraphe (phrases collées).
-// Si des chapitres existent, ils sont insérés via TranscriptWithChaptersSplit(0, asCollapsed).
+// Si des chapitres existent, ils sont insérés via transcriptWithChaptersSplit, recalés selon ChapterSnap.
 func (t Transcript) Collapsed() string {
 	if len(t.Phrases) == 0 {
 		return ""
@@ -49,7 +50,7 @@ func (t Transcript) Collapsed() string {
 		// pas de chapitres
 		return t.CollapsedNoChapters()
 	}
-	return t.transcriptWithChaptersSplit(0, asCollapsed)
+	return t.transcriptWithChaptersSplit(t.ChapterSnap, asCollapsed)
 }
 
 // CollapsedNoChapters retourne le transcript en un seul paragraphe (tout sur une ligne).
@@ -57,28 +58,25 @@ func (t Transcript) Collapsed() string {
 func (t Transcript) CollapsedNoChapters() string {
 	parts := make([]string, 0, len(t.Phrases))
 	for _, p := range t.Phrases {
-		parts = append(parts, strings.TrimSpace(p.Text))
+		parts = append(parts, strings.TrimSpace(p.displayText()))
 	}
 	// join par un espace unique et trim final
 	out := strings.TrimSpace(strings.Join(parts, " ")) + "\n"
 	return out
 }
 
-// SaveAs écrit le transcript dans le fichier `path` selon le format donné.
-// Utilise les constantes model.Format (txt, md, srt, vtt, json3).
+// SaveAs écrit le transcript dans le fichier `path` selon le format donné (voir Render :
+// même sortie que SaveTranscript). Pour le txt historique, une phrase par ligne
+// sans chapitres ni notice, écrire PlainNoChapters.
 // Pour json3, SaveAs retourne une erreur : le raw JSON n'est pas stocké dans Transcript.
 func (t Transcript) SaveAs(path string, format model.Format) error {
-	var data []byte
-	var err error
-
-	switch format {
-	case model.FormatTXT:
-		data = []byte(t.PlainNoChapters())
-	case model.FormatJSON3:
+	if format == model.FormatJSON3 {
 		// Transcript ne conserve pas le raw json3 original ; renvoyer une erreur
 		return errors.New("SaveAs format json3 non supporté depuis Transcript (utiliser SubtitleDownload.Data)")
-	default:
-		return fmt.Errorf("format inconnu dans SaveAs: %s", format)
+	}
+	data, err := t.Render(format)
+	if err != nil {
+		return fmt.Errorf("SaveAs: %w", err)
 	}
 
 	// écrire le fichier (écrase si existe)
@@ -91,7 +89,7 @@ func (t Transcript) SaveAs(path string, format model.Format) error {
 func (t Transcript) Filename(format model.Format) (string, error) {
 	base := strings.TrimSpace(t.Title)
 	base = fsutil.SanitizeFilename(base)
-	if format.IsTextual() {
+	if format.IsTranscriptOutput() {
 		return base + format.Extension(), nil
 	}
 	return "", fmt.Errorf("format inconnu dans Filename: %q", format)
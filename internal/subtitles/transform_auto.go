@@ -2,7 +2,6 @@ package subtitles
 
 import (
 	"strings"
-	"unicode"
 	"unicode/utf8"
 )
 
@@ -26,58 +25,66 @@ const (
 // Version simplifiée : on considère chaque seg comme une unité atomique.
 // On n'essaie PAS de découper un seg en plusieurs sous-phrases même si
 // celui-ci contient plusieurs terminators (cas extrêmement rare en ASR).
-func TransformAutoRawToPhrases(raw rawJSON3) ([]Phrase, error) {
+// Un seg qui finit par une abréviation connue ("Dr.") ne termine pas la phrase.
+// Si opts.Speakers, un seg qui commence par ">>" (changement de locuteur) ouvre une nouvelle phrase.
+func TransformAutoRawToPhrases(raw rawJSON3, opts Options) ([]Phrase, error) {
 	var phrases []Phrase
 	if len(raw.Events) == 0 {
 		return phrases, nil
 	}
+	rules := opts.splitRules()
 
 	var (
-		currentSb      strings.Builder      // accumulateur de la phrase courante
-		currentStartMs int64           = -1 // timestamp du premier mot de la phrase en cours
-		lastWordTs     int64           = -1 // timestamp du dernier mot vu (pour pause)
+		buf            []byte      // texte de la phrase courante, réutilisé d'une phrase à l'autre
+		runeCount      int         // runes de buf, tenu à jour à chaque seg
+		wordCount      int         // mots de buf (somme par seg, voir rules.countWords)
+		currentStartMs int64  = -1 // timestamp du premier mot de la phrase en cours
+		lastWordTs     int64  = -1 // timestamp du dernier mot vu (pour pause)
+		words          []Word      // timings par mot de la phrase courante
+		speaker        string      // locuteur courant (S1/S2, voir speakers.go)
+		turns          int         // nombre de ">>" rencontrés
 	)
+	joiner := phraseJoiner(rules.lang)
+	joinerRunes := utf8.RuneCountInString(joiner)
 
 	commit := func() {
-		txt := strings.TrimSpace(currentSb.String())
-		if txt == "" {
-			currentSb.Reset()
-			currentStartMs = -1
-			return
-		}
-		// par défaut on met le timestamp du premier mot si disponible,
-		// sinon on utilise lastWordTs (fallback)
-		ts := lastWordTs
-		if currentStartMs >= 0 {
-			ts = currentStartMs
-		}
-		p := Phrase{
-			TimestampMs: ts,
-			Text:        txt,
+		if len(buf) > 0 {
+			// par défaut on met le timestamp du premier mot si disponible,
+			// sinon on utilise lastWordTs (fallback)
+			ts := lastWordTs
+			if currentStartMs >= 0 {
+				ts = currentStartMs
+			}
+			phrases = append(phrases, Phrase{
+				TimestampMs: ts,
+				Text:        string(buf),
+				RuneCount:   runeCount,
+				WordCount:   wordCount,
+				Speaker:     speaker,
+				Words:       words,
+			})
 		}
-		p.RuneCount = utf8.RuneCountInString(strings.TrimSpace(p.Text))
-		p.WordCount = len(strings.FieldsFunc(p.Text, unicode.IsSpace))
-
-		phrases = append(phrases, p)
-
-		// reset pour la phrase suivante
-		currentSb.Reset()
+		// reset pour la phrase suivante ; la capacité de words suit la dernière phrase
+		buf = buf[:0]
+		runeCount, wordCount = 0, 0
 		currentStartMs = -1
+		words = make([]Word, 0, cap(words))
 		// lastWordTs reste inchangé (utile comme fallback)
 	}
 
+	// appendSegmentText ajoute un seg déjà normalisé (garde un espace entre
+	// fragments, aucun en japonais, chinois...) et met les compteurs à jour.
 	appendSegmentText := func(segText string) {
-		segText = normalizeWhitespace(segText)
 		if segText == "" {
 			return
 		}
-		if currentSb.Len() == 0 {
-			currentSb.WriteString(segText)
-		} else {
-			// garder un espace entre fragments
-			currentSb.WriteByte(' ')
-			currentSb.WriteString(strings.TrimSpace(segText))
+		if len(buf) > 0 {
+			buf = append(buf, joiner...)
+			runeCount += joinerRunes
 		}
+		buf = append(buf, segText...)
+		runeCount += utf8.RuneCountInString(segText)
+		wordCount += rules.countWords(segText)
 	}
 
 	// boucle principale sur Events, voir raw_types.go
@@ -87,20 +94,36 @@ func TransformAutoRawToPhrases(raw rawJSON3) ([]Phrase, error) {
 			continue
 		}
 
+		// fin de l'event : borne provisoire des mots (affinée par finalizeWordEnds)
+		var evEnd int64
+		if ev.TStartMs != nil && ev.DDurationMs != nil {
+			evEnd = *ev.TStartMs + *ev.DDurationMs
+		}
+
 		// parcourir les segs (chaque seg est traité comme une unité)
 		for _, seg := range ev.Segs {
-			s := seg.Utf8
+			s := voiceTurns(seg.Utf8, opts.Speakers)
 			s = strings.ReplaceAll(s, "\\n", "\n")
 			// ignorer segs vides ou contenant uniquement des espaces / \n
 			if strings.TrimSpace(s) == "" || s == "\n" {
 				continue
 			}
 
+			// ">>" : changement de locuteur
+			if rest, ok := strings.CutPrefix(strings.TrimSpace(s), ">>"); ok && opts.Speakers {
+				commit()
+				speaker = nextSpeaker(speaker, "")
+				turns++
+				if s = strings.TrimSpace(rest); s == "" {
+					continue
+				}
+			}
+
 			// calcul du timestamp absolu pour ce seg (mot)
 			ts := calculateAbsTime(ev, seg)
 			if ts > 0 {
 				// si pause plus longue que le seuil, on coupe la phrase en cours
-				if lastWordTs >= 0 && (ts-lastWordTs) > pauseThresholdMs && currentSb.Len() > 0 {
+				if lastWordTs >= 0 && (ts-lastWordTs) > pauseThresholdMs && len(buf) > 0 {
 					commit()
 				}
 				// mettre à jour lastWordTs et initialiser currentStartMs si premier mot de la phrase
@@ -110,10 +133,12 @@ func TransformAutoRawToPhrases(raw rawJSON3) ([]Phrase, error) {
 				}
 			}
 
-			appendSegmentText(s)
+			segText := normalizeWhitespace(s)
+			appendSegmentText(segText)
+			words = append(words, Word{Text: segText, StartMs: ts, EndMs: evEnd})
 
 			// sécurité : limiter la longueur en nombre de mots
-			if len(strings.Fields(currentSb.String())) >= maxWordsPerPhrase {
+			if wordCount >= maxWordsPerPhrase {
 				commit()
 				continue
 			}
@@ -121,7 +146,7 @@ func TransformAutoRawToPhrases(raw rawJSON3) ([]Phrase, error) {
 			// décision de commit basée uniquement sur le dernier rune du seg
 			trimmed := trimTrailingClosers(s)         // enlève quotes/closers en fin
 			lastRune, ok := lastNonSpaceRune(trimmed) // rune utile finale
-			if ok && isSentenceTerminatorRune(lastRune) {
+			if ok && isSentenceTerminatorRune(lastRune) && !rules.endsOnAbbreviation(trimmed) {
 				// si le seg se termine par un terminator, on commit la phrase
 				commit()
 				continue
@@ -132,5 +157,10 @@ func TransformAutoRawToPhrases(raw rawJSON3) ([]Phrase, error) {
 
 	// flush final
 	commit()
+	finalizeWordEnds(phrases)
+	dropLoneSpeaker(phrases, turns)
+	if opts.MergeShortPhrases {
+		phrases = mergeShortPhrases(phrases, opts.MinPhraseWordsToKeep, rules)
+	}
 	return phrases, nil
 }
@@ -1,6 +1,7 @@
 package subtitles
 
 import (
+	"math"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -19,13 +20,29 @@ func calculateAbsTime(ev rawEvent, seg rawSeg) int64 {
 	return base
 }
 
+// isSentenceTerminatorRune : ponctuation de fin de phrase, toutes écritures
+// (latin, CJK pleine chasse, arabe/ourdou, devanagari, arménien, éthiopien, birman...).
+// Les ¿ ¡ espagnols sont des ouvrants (isOpenerRune), pas des terminators.
 func isSentenceTerminatorRune(r rune) bool {
-	return r == '.' || r == '!' || r == '?'
+	switch r {
+	case '.', '!', '?',
+		'。', '！', '？', '｡', '．', // CJK
+		'؟', '۔', // arabe, ourdou
+		'।', '॥', // devanagari (danda)
+		'։',      // arménien
+		'።', '፧', // éthiopien
+		'။',      // birman
+		'\u037e', // point d'interrogation grec
+		'‼', '⁇', '⁈', '⁉':
+		return true
+	}
+	return false
 }
 
 func isCloserRune(r rune) bool {
 	switch r {
-	case '"', '\'', '”', '’', ')', ']', '}', '»':
+	case '"', '\'', '”', '’', ')', ']', '}', '»', '›',
+		'」', '』', '）', '】', '〕', '〉', '》', '〗', '〙', '〛', '］', '｝', '＂', '＇':
 		return true
 	}
 	return false
@@ -70,7 +87,121 @@ func lastNonSpaceRune(s string) (rune, bool) {
 	return 0, false
 }
 
-// normalizeWhitespace nettoie les espace : un seul espace entre mots, aucun en début/fin
+// normalizeWhitespace nettoie les espace : un seul espace entre mots, aucun en début/fin.
+// Sans allocation quand le texte est déjà propre (cas courant des segs ASR " mot").
 func normalizeWhitespace(s string) string {
-	return strings.TrimSpace(strings.Join(strings.Fields(s), " "))
+	s = strings.TrimSpace(s)
+	if isNormalizedSpace(s) {
+		return s
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// isNormalizedSpace : s (déjà trimé) ne contient que des espaces simples entre les mots.
+func isNormalizedSpace(s string) bool {
+	prevSpace := false
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			prevSpace = false
+			continue
+		}
+		if r != ' ' || prevSpace {
+			return false
+		}
+		prevSpace = true
+	}
+	return true
+}
+
+// finalizeWordEnds borne la fin de chaque mot au début du mot suivant (les events
+// json3 se chevauchent à l'affichage, leur fin n'est qu'une borne haute) puis
+// fixe Phrase.EndMs sur la fin du dernier mot. Parcours à rebours, en place.
+func finalizeWordEnds(phrases []Phrase) {
+	nextStart := int64(-1)
+	for i := len(phrases) - 1; i >= 0; i-- {
+		ws := phrases[i].Words
+		for j := len(ws) - 1; j >= 0; j-- {
+			w := &ws[j]
+			if nextStart >= 0 && (w.EndMs <= w.StartMs || w.EndMs > nextStart) {
+				w.EndMs = nextStart
+			}
+			w.EndMs = max(w.EndMs, w.StartMs)
+			nextStart = w.StartMs
+		}
+		if len(ws) > 0 {
+			phrases[i].EndMs = ws[len(ws)-1].EndMs
+		}
+	}
+}
+
+// estimateWords répartit les mots de text à partir de startMs, perRuneMs
+// millisecondes par rune (espaces compris) : timings estimés des sous-titres manuels.
+func estimateWords(text string, startMs int64, perRuneMs float64) []Word {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil
+	}
+	words := make([]Word, 0, len(fields))
+	offset := 0 // runes consommées avant le mot courant
+	for _, f := range fields {
+		n := utf8.RuneCountInString(f)
+		words = append(words, Word{
+			Text:    f,
+			StartMs: startMs + int64(math.Round(perRuneMs*float64(offset))),
+			EndMs:   startMs + int64(math.Round(perRuneMs*float64(offset+n))),
+		})
+		offset += n + 1
+	}
+	return words
+}
+
+// mergeShortPhrases rattache les phrases de moins de minWords mots ("Yes.", "Okay.")
+// à la phrase précédente, ou à la suivante pour la première ou après une longue pause
+// (pauseThresholdMs). Une phrase courte isolée par deux pauses est conservée, comme
+// une réplique courte d'un autre locuteur.
+func mergeShortPhrases(phrases []Phrase, minWords int, rules splitRules) []Phrase {
+	if minWords <= 1 || len(phrases) < 2 {
+		return phrases
+	}
+	near := func(a, b Phrase) bool {
+		end := max(a.EndMs, a.TimestampMs)
+		return a.Speaker == b.Speaker && b.TimestampMs-end <= pauseThresholdMs
+	}
+
+	out := make([]Phrase, 0, len(phrases))
+	var pending *Phrase // phrase courte en attente d'être préfixée à la suivante
+	for _, p := range phrases {
+		if pending != nil {
+			if near(*pending, p) {
+				p = concatPhrases(*pending, p, rules)
+			} else {
+				out = append(out, *pending)
+			}
+			pending = nil
+		}
+		if phraseWords(p) >= minWords {
+			out = append(out, p)
+			continue
+		}
+		if n := len(out); n > 0 && near(out[n-1], p) {
+			out[n-1] = concatPhrases(out[n-1], p, rules)
+			continue
+		}
+		q := p
+		pending = &q
+	}
+	if pending != nil {
+		out = append(out, *pending)
+	}
+	return out
+}
+
+// concatPhrases concatène deux phrases consécutives (texte, bornes, mots, compteurs).
+func concatPhrases(a, b Phrase, rules splitRules) Phrase {
+	a.Text = normalizeWhitespace(rules.join(a.Text, b.Text))
+	a.EndMs = max(a.EndMs, b.EndMs)
+	a.Words = append(append([]Word(nil), a.Words...), b.Words...)
+	a.RuneCount = utf8.RuneCountInString(a.Text)
+	a.WordCount = rules.countWords(a.Text)
+	return a
 }
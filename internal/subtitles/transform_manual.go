@@ -2,7 +2,6 @@ package subtitles
 
 import (
 	"math"
-	"regexp"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -14,8 +13,6 @@ type SplitPiece struct {
 	EndWithTerminator bool // true si la pièce se termine par . ! ou ?
 }
 
-var reMultiSpace = regexp.MustCompile(`\s+`)
-
 // EventText : assemble et nettoie le texte d'un event (ré-usage de cleanSeg)
 func EventText(ev rawEvent) string {
 	var parts []string
@@ -32,17 +29,17 @@ func EventText(ev rawEvent) string {
 // cleanSeg normalise un seg : convertit les "\n" et "\\n" en espaces,
 // remplace les séquences d'espaces par un seul espace, et trim.
 func cleanSeg(s string) string {
-	// remplacer les séquences d'échappement de newline
+	// remplacer les séquences d'échappement de newline ("\n" réel : un espace comme un autre)
 	s = strings.ReplaceAll(s, "\\n", " ")
-	s = strings.ReplaceAll(s, "\n", " ")
 	// normaliser les espaces (tabs, multiples espaces, etc.)
-	s = reMultiSpace.ReplaceAllString(s, " ")
-	return strings.TrimSpace(s)
+	return normalizeWhitespace(s)
 }
 
 // splitSegOffsetsString : même rôle que splitSegOffsets([]rune) mais sans allocation de []rune.
 // Retourne []SplitPiece avec EndRune compté en runes (pas d'octets).
-func splitSegOffsetsString(s string) []SplitPiece {
+// rules évite les coupures après une abréviation, un nombre ou des points de
+// suspension qui ne terminent pas la phrase (voir splitRules.continuesSentence).
+func splitSegOffsetsString(s string, rules splitRules) []SplitPiece {
 	if s == "" {
 		return nil
 	}
@@ -94,6 +91,19 @@ func splitSegOffsetsString(s string) []SplitPiece {
 				}
 				// incrémente le compteur de runes consommées pour inclure les espaces
 				runeIndex += spaceCount
+				// abréviation, nombre ou "..." suivi d'une minuscule : la phrase continue
+				next := s[j:]
+				if k := strings.IndexFunc(next, unicode.IsSpace); k >= 0 {
+					next = next[:k]
+				}
+				if rules.continuesSentence(lastField(sb.String()), terminatorBuf.String(), next) {
+					sb.WriteString(terminatorBuf.String())
+					sb.WriteByte(' ')
+					terminatorBuf.Reset()
+					candidat = false
+					idx = j
+					continue
+				}
 				// construire la pièce commitée
 				combined := sb.String() + terminatorBuf.String()
 				text := normalizeWhitespace(combined)
@@ -112,6 +122,18 @@ func splitSegOffsetsString(s string) []SplitPiece {
 				continue
 			}
 
+			// ponctuation pleine chasse (。！？) : fin de phrase même sans espace après
+			if strings.ContainsFunc(terminatorBuf.String(), isSpacelessTerminatorRune) {
+				text := normalizeWhitespace(sb.String() + terminatorBuf.String())
+				if text != "" {
+					out = append(out, SplitPiece{Text: text, EndRune: runeIndex, EndWithTerminator: true})
+				}
+				sb.Reset()
+				terminatorBuf.Reset()
+				candidat = false
+				continue // la rune courante ouvre la pièce suivante
+			}
+
 			// sinon : faux-positif (ex: 2.6) -> rattacher terminatorBuf au texte et re-traiter rune courante
 			sb.WriteString(terminatorBuf.String())
 			terminatorBuf.Reset()
@@ -135,7 +157,7 @@ func splitSegOffsetsString(s string) []SplitPiece {
 		runeIndex++
 	}
 
-	// vidage final
+	// vidage final ("Dr." en fin d'event : la phrase continue dans l'event suivant)
 	if candidat && terminatorBuf.Len() > 0 {
 		combined := sb.String() + terminatorBuf.String()
 		text := normalizeWhitespace(combined)
@@ -143,7 +165,7 @@ func splitSegOffsetsString(s string) []SplitPiece {
 			out = append(out, SplitPiece{
 				Text:              text,
 				EndRune:           runeIndex,
-				EndWithTerminator: true,
+				EndWithTerminator: !rules.continuesSentence(lastField(sb.String()), terminatorBuf.String(), ""),
 			})
 		}
 	} else if sb.Len() > 0 {
@@ -164,13 +186,51 @@ func splitSegOffsetsString(s string) []SplitPiece {
 // - découpe chaque event en SplitPiece (via splitSegOffsets sur le texte complet de l'event)
 // - calcule perRuneMs à partir de event.Duration / #runes
 // - construit et finalise des phrases qui peuvent traverser plusieurs events
-func TransformManualRawToPhrases(raw rawJSON3) ([]Phrase, error) {
+// - si opts.Speakers, coupe aux changements de locuteur (tiret, >>, "NOM:") et renseigne Phrase.Speaker
+// - fusionne les phrases trop courtes si opts.MergeShortPhrases
+func TransformManualRawToPhrases(raw rawJSON3, opts Options) ([]Phrase, error) {
 	var out []Phrase
+	rules := opts.splitRules()
 
 	var currBuilder strings.Builder
 	var currStartMs int64 = -1 // timestamp de début de la phrase en construction; -1 = unset
+	var currEndMs int64        // fin estimée de la dernière piece ajoutée
+	var currWords []Word       // timings estimés (perRuneMs) des mots de la phrase
+	var currSpeaker string     // locuteur de la réplique en cours (voir speakers.go)
+	turnCount := 0             // répliques marquées (tiret, >>, étiquette)
 
-	for _, ev := range raw.Events {
+	finalize := func() {
+		text := normalizeWhitespace(currBuilder.String())
+		if text != "" {
+			// currStartMs est posé dès la 1ère piece ; protection si jamais ce n'est pas le cas
+			if currStartMs == -1 {
+				currStartMs = 0
+			}
+			out = append(out, Phrase{
+				TimestampMs: currStartMs,
+				EndMs:       currEndMs,
+				Text:        text,
+				RuneCount:   utf8.RuneCountInString(text),
+				WordCount:   rules.countWords(text),
+				Speaker:     currSpeaker,
+				Words:       currWords,
+			})
+		}
+		currBuilder.Reset()
+		currStartMs = -1
+		currWords = nil
+	}
+
+	texts := make([]string, len(raw.Events))
+	for i, ev := range raw.Events {
+		texts[i] = voiceTurns(EventText(ev), opts.Speakers)
+	}
+	var labels map[string]struct{}
+	if opts.Speakers {
+		labels = speakerLabels(texts)
+	}
+
+	for i, ev := range raw.Events {
 		// récupérer start/duration de l'event (sécurisé sur pointeurs)
 		var evStart, evDur int64
 		if ev.TStartMs != nil {
@@ -180,28 +240,20 @@ func TransformManualRawToPhrases(raw rawJSON3) ([]Phrase, error) {
 			evDur = *ev.DDurationMs
 		}
 
-		evText := EventText(ev)
+		evText := texts[i]
 		if strings.TrimSpace(evText) == "" {
 			// rien à faire pour cet event
 			continue
 		}
 
-		// découper le texte de l'event en pieces (avec EndRune)
-		pieces := splitSegOffsetsString(evText)
-
-		// fallback si splitSegOffsets renvoie nil (on garde l'event entier)
-		if len(pieces) == 0 {
-			// tout l'event comme une seule piece
-			pieces = []SplitPiece{{
-				Text:              normalizeWhitespace(evText),
-				EndRune:           utf8.RuneCountInString(evText),
-				EndWithTerminator: false,
-			}}
-
+		// répliques de l'event ; les marqueurs (tiret, >>, "NOM:") ne prennent pas de temps
+		turns := []speakerTurn{{text: evText}}
+		runesInEvent := utf8.RuneCountInString(evText)
+		if opts.Speakers {
+			turns, runesInEvent = splitTurns(evText, labels)
 		}
 
 		// per-rune ms pour cet event
-		runesInEvent := utf8.RuneCountInString(evText)
 		var perRuneMs float64
 		if runesInEvent > 0 && evDur > 0 {
 			perRuneMs = float64(evDur) / float64(runesInEvent)
@@ -209,62 +261,66 @@ func TransformManualRawToPhrases(raw rawJSON3) ([]Phrase, error) {
 			perRuneMs = 0
 		}
 
-		prevEnd := 0 // nombre de runes consommées avant la piece courante (dans l'event)
-		for _, p := range pieces {
-			startRuneInEvent := prevEnd
-			// startMs estimé pour cette piece (si besoin)
-			pieceStartMs := evStart + int64(math.Round(perRuneMs*float64(startRuneInEvent)))
-
-			// si aucune phrase en cours, on initialise son timestamp au début de cette piece
-			if currBuilder.Len() == 0 {
-				currStartMs = pieceStartMs
+		// un changement de locuteur termine la phrase en cours
+		for _, turn := range turns {
+			if turn.marked {
+				finalize()
+				currSpeaker = nextSpeaker(currSpeaker, turn.label)
+				turnCount++
 			}
+			if turn.text == "" {
+				continue
+			}
+
+			// découper le texte de la réplique en pieces (avec EndRune)
+			pieces := splitSegOffsetsString(turn.text, rules)
 
-			// fusionner la piece au builder courant (avec un espace si nécessaire)
-			if currBuilder.Len() > 0 {
-				currBuilder.WriteString(" ")
+			// fallback si splitSegOffsets renvoie nil (on garde la réplique entière)
+			if len(pieces) == 0 {
+				pieces = []SplitPiece{{
+					Text:              normalizeWhitespace(turn.text),
+					EndRune:           utf8.RuneCountInString(turn.text),
+					EndWithTerminator: false,
+				}}
 			}
-			currBuilder.WriteString(p.Text)
 
-			if p.EndWithTerminator {
-				// finaliser la phrase courante
-				text := normalizeWhitespace(currBuilder.String())
-				if strings.TrimSpace(text) != "" {
-					rc := utf8.RuneCountInString(text)
-					wc := len(strings.Fields(text))
-					out = append(out, Phrase{
-						TimestampMs: currStartMs,
-						Text:        text,
-						RuneCount:   rc,
-						WordCount:   wc,
-					})
+			prevEnd := 0 // nombre de runes consommées avant la piece courante (dans la réplique)
+			for _, p := range pieces {
+				// offsets de la piece dans l'event
+				startRuneInEvent := turn.startRune + prevEnd
+				endRuneInEvent := turn.startRune + p.EndRune
+				// startMs estimé pour cette piece (si besoin)
+				pieceStartMs := evStart + int64(math.Round(perRuneMs*float64(startRuneInEvent)))
+
+				// si aucune phrase en cours, on initialise son timestamp au début de cette piece
+				if currBuilder.Len() == 0 {
+					currStartMs = pieceStartMs
+				}
+
+				// fusionner la piece au builder courant (espace selon la langue, voir phraseJoiner)
+				if currBuilder.Len() > 0 {
+					currBuilder.WriteString(phraseJoiner(rules.lang))
+				}
+				currBuilder.WriteString(p.Text)
+				currWords = append(currWords, estimateWords(p.Text, pieceStartMs, perRuneMs)...)
+				currEndMs = evStart + int64(math.Round(perRuneMs*float64(endRuneInEvent)))
+
+				if p.EndWithTerminator {
+					// finaliser la phrase courante (la suivante commencera après cette piece)
+					finalize()
 				}
-				// reset builder (la phrase suivante commencera après cette piece)
-				currBuilder.Reset()
-				currStartMs = -1
+				// avancer prevEnd
+				prevEnd = p.EndRune
 			}
-			// avancer prevEnd
-			prevEnd = p.EndRune
 		}
 	}
 
 	// flush final si reste non terminé
-	if strings.TrimSpace(currBuilder.String()) != "" {
-		// si currStartMs n'a jamais été initialisé, on le met à 0 (ou la moindre start connue) ;
-		// en pratique currStartMs devrait toujours avoir été set dès la 1ère piece, mais on protège.
-		if currStartMs == -1 {
-			currStartMs = 0
-		}
-		text := normalizeWhitespace(currBuilder.String())
-		rc := utf8.RuneCountInString(text)
-		wc := len(strings.Fields(text))
-		out = append(out, Phrase{
-			TimestampMs: currStartMs,
-			Text:        text,
-			RuneCount:   rc,
-			WordCount:   wc,
-		})
-	}
+	finalize()
+	dropLoneSpeaker(out, turnCount)
 
+	if opts.MergeShortPhrases {
+		out = mergeShortPhrases(out, opts.MinPhraseWordsToKeep, rules)
+	}
 	return out, nil
 }
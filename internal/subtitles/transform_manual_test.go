@@ -25,9 +25,17 @@ func TestSplitSegOffsetsString_SimpleAndDecimal(t *testing.T) {
 			wantEndRuneIsRuneC: true,
 		},
 		{
-			name:               "ellipsis and terminator",
+			// points de suspension suivis d'une minuscule : la phrase continue
+			name:               "ellipsis then lowercase",
 			in:                 "Wait... what?",
-			wantTexts:          []string{"Wait...", "what?"},
+			wantTexts:          []string{"Wait... what?"},
+			wantEndWithTerm:    []bool{true},
+			wantEndRuneIsRuneC: true,
+		},
+		{
+			name:               "ellipsis then uppercase",
+			in:                 "Wait... What?",
+			wantTexts:          []string{"Wait...", "What?"},
 			wantEndWithTerm:    []bool{true, true},
 			wantEndRuneIsRuneC: true,
 		},
@@ -49,7 +57,7 @@ func TestSplitSegOffsetsString_SimpleAndDecimal(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			parts := splitSegOffsetsString(tc.in)
+			parts := splitSegOffsetsString(tc.in, Options{}.splitRules())
 			if len(parts) != len(tc.wantTexts) {
 				t.Fatalf("got %d parts, want %d : %#v", len(parts), len(tc.wantTexts), parts)
 			}
@@ -80,6 +88,77 @@ func TestSplitSegOffsetsString_SimpleAndDecimal(t *testing.T) {
 	}
 }
 
+func TestSplitSegOffsetsString_Abbreviations(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		in   string
+		want []string
+	}{
+		{"en title", Options{Lang: "en"}, "Dr. Smith arrived. He sat.", []string{"Dr. Smith arrived.", "He sat."}},
+		{"en e.g.", Options{Lang: "en"}, "Use tools, e.g. a hammer. Then stop.", []string{"Use tools, e.g. a hammer.", "Then stop."}},
+		{"fr monsieur", Options{Lang: "fr"}, "M. Dupont est là. Bonjour.", []string{"M. Dupont est là.", "Bonjour."}},
+		{"de z.B.", Options{Lang: "de"}, "Obst, z.B. Äpfel. Gut.", []string{"Obst, z.B. Äpfel.", "Gut."}},
+		{"es sr", Options{Lang: "es-419"}, "El Sr. García habla. Bien.", []string{"El Sr. García habla.", "Bien."}},
+		{"etc then uppercase", Options{Lang: "en"}, "Apples, pears etc. Then more.", []string{"Apples, pears etc.", "Then more."}},
+		{"etc then lowercase", Options{Lang: "fr"}, "Des pommes etc. et des poires.", []string{"Des pommes etc. et des poires."}},
+		{"number then lowercase", Options{Lang: "fr"}, "Le 3. mai on part. Fin.", []string{"Le 3. mai on part.", "Fin."}},
+		{"number then uppercase", Options{Lang: "en"}, "It was 1999. Then it ended.", []string{"It was 1999.", "Then it ended."}},
+		{"custom abbreviation", Options{Lang: "en", Abbreviations: map[string]struct{}{"Ref.": {}}}, "See ref. Five now.", []string{"See ref. Five now."}},
+		{"break on abbreviations", Options{Lang: "en", BreakOnAbbreviations: true}, "Dr. Smith arrived.", []string{"Dr.", "Smith arrived."}},
+		{"lowercase m is not monsieur", Options{Lang: "fr"}, "Il fait 5 m. Ensuite on part.", []string{"Il fait 5 m.", "Ensuite on part."}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := splitSegOffsetsString(tc.in, tc.opts.splitRules())
+			var got []string
+			for _, p := range parts {
+				got = append(got, p.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRawJSON3ToPhrases_AbbreviationAcrossEvents(t *testing.T) {
+	// "Dr." en fin d'event ne termine pas la phrase
+	raw := rawJSON3{Events: []rawEvent{
+		{TStartMs: ptrInt64(0), DDurationMs: ptrInt64(1000), Segs: []rawSeg{{Utf8: "We met Dr."}}},
+		{TStartMs: ptrInt64(1000), DDurationMs: ptrInt64(1000), Segs: []rawSeg{{Utf8: "Smith today."}}},
+	}}
+	phrases, _ := TransformManualRawToPhrases(raw, Options{Lang: "en"})
+	if len(phrases) != 1 || phrases[0].Text != "We met Dr. Smith today." {
+		t.Fatalf("phrases = %#v", phrases)
+	}
+}
+
+func TestMergeShortPhrases(t *testing.T) {
+	raw := rawJSON3{Events: []rawEvent{
+		{TStartMs: ptrInt64(0), DDurationMs: ptrInt64(2000), Segs: []rawSeg{{Utf8: "Yes. We start with the basics."}}},
+		{TStartMs: ptrInt64(2000), DDurationMs: ptrInt64(1500), Segs: []rawSeg{{Utf8: "Then we continue slowly. Okay."}}},
+		{TStartMs: ptrInt64(9000), DDurationMs: ptrInt64(500), Segs: []rawSeg{{Utf8: "Bye."}}},
+	}}
+	phrases, _ := TransformManualRawToPhrases(raw, Options{MergeShortPhrases: true, MinPhraseWordsToKeep: 3})
+	want := []string{
+		"Yes. We start with the basics.",
+		"Then we continue slowly. Okay.",
+		"Bye.", // isolé par une pause : conservé
+	}
+	if len(phrases) != len(want) {
+		t.Fatalf("got %d phrases: %#v", len(phrases), phrases)
+	}
+	for i, w := range want {
+		if phrases[i].Text != w {
+			t.Errorf("phrase %d = %q; want %q", i, phrases[i].Text, w)
+		}
+	}
+	if phrases[0].TimestampMs != 0 || phrases[0].WordCount != 6 {
+		t.Errorf("phrase 0 : ts=%d words=%d", phrases[0].TimestampMs, phrases[0].WordCount)
+	}
+}
+
 // --- Tests pour RawJSON3ToPhrases ----------------------------------------
 
 func TestRawJSON3ToPhrases_MultiEvent_JoinAcrossEvents(t *testing.T) {
@@ -103,7 +182,7 @@ func TestRawJSON3ToPhrases_MultiEvent_JoinAcrossEvents(t *testing.T) {
 		},
 	}
 
-	phrases, _ := TransformManualRawToPhrases(raw)
+	phrases, _ := TransformManualRawToPhrases(raw, Options{})
 	if len(phrases) != 1 {
 		t.Fatalf("expected 1 phrase, got %d: %#v", len(phrases), phrases)
 	}
@@ -133,7 +212,7 @@ func TestRawJSON3ToPhrases_SameEventMultiplePhrases(t *testing.T) {
 		},
 	}
 
-	phrases, _ := TransformManualRawToPhrases(raw)
+	phrases, _ := TransformManualRawToPhrases(raw, Options{})
 	if len(phrases) != 2 {
 		t.Fatalf("expected 2 phrases, got %d: %#v", len(phrases), phrases)
 	}
@@ -0,0 +1,56 @@
+package subtitles
+
+import "testing"
+
+func TestAutoPhraseWordTimings(t *testing.T) {
+	// deux events json3 qui se chevauchent à l'affichage (500-4500 et 2000-6000)
+	raw := rawJSON3{Events: []rawEvent{
+		{TStartMs: ptrInt64(500), DDurationMs: ptrInt64(4000), Segs: []rawSeg{
+			{Utf8: "hello"}, {Utf8: " world.", TOffsetMs: ptrInt64(600)},
+		}},
+		{TStartMs: ptrInt64(2000), DDurationMs: ptrInt64(4000), Segs: []rawSeg{
+			{Utf8: "bye", TOffsetMs: ptrInt64(0)}, {Utf8: " now", TOffsetMs: ptrInt64(400)},
+		}},
+	}}
+	phrases, err := TransformAutoRawToPhrases(raw, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phrases) != 2 {
+		t.Fatalf("got %d phrases: %+v", len(phrases), phrases)
+	}
+	// "world." finit au début de "bye", pas à la fin de son event
+	if got := phrases[0].Words; len(got) != 2 || got[1].StartMs != 1100 || got[1].EndMs != 2000 {
+		t.Errorf("mots phrase 0 = %+v", got)
+	}
+	if phrases[0].EndMs != 2000 || phrases[0].DurationMs() != 1500 {
+		t.Errorf("phrase 0 : EndMs=%d DurationMs=%d", phrases[0].EndMs, phrases[0].DurationMs())
+	}
+	// dernier mot : borné par la fin de son event
+	if phrases[1].EndMs != 6000 {
+		t.Errorf("phrase 1 : EndMs=%d, want 6000", phrases[1].EndMs)
+	}
+}
+
+func TestManualPhraseEstimatedTimings(t *testing.T) {
+	// 20 runes sur 2000 ms => 100 ms par rune
+	raw := rawJSON3{Events: []rawEvent{
+		{TStartMs: ptrInt64(1000), DDurationMs: ptrInt64(2000), Segs: []rawSeg{{Utf8: "Hello there. Bye now"}}},
+	}}
+	phrases, err := TransformManualRawToPhrases(raw, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phrases) != 2 {
+		t.Fatalf("got %d phrases: %+v", len(phrases), phrases)
+	}
+	if p := phrases[0]; p.TimestampMs != 1000 || p.EndMs != 2300 {
+		t.Errorf("phrase 0 : %d-%d, want 1000-2300", p.TimestampMs, p.EndMs)
+	}
+	if p := phrases[1]; p.TimestampMs != 2300 || p.EndMs != 3000 {
+		t.Errorf("phrase 1 : %d-%d, want 2300-3000", p.TimestampMs, p.EndMs)
+	}
+	if w := phrases[1].Words; len(w) != 2 || w[1].Text != "now" || w[1].StartMs != 2700 || w[1].EndMs != 3000 {
+		t.Errorf("mots phrase 1 = %+v", w)
+	}
+}
@@ -15,16 +15,23 @@ var ErrNoSubtitle = errors.New("no subtitle available for given source")
 
 // SubtitleDownload contient la piste + contexte utile (titre) + payload.
 type SubtitleDownload struct {
-	Title string
-	Track model.SubtitleTrack
-	Data  []byte // nil tant que non téléchargé
+	Title  string
+	Track  model.SubtitleTrack
+	Reason SelectReason // pourquoi cette piste (voir SelectTrack)
+	Data   []byte       // nil tant que non téléchargé (et pour un json3 décodé en flux, voir DownloadTrack)
+	// parsed : events json3 décodés pendant le téléchargement (nil sinon)
+	parsed *rawJSON3
 }
 
+// Options : réglages de la découpe en phrases (TransformRawToPhrases).
+// La valeur zéro ne coupe pas après les abréviations connues et ne fusionne rien.
 type Options struct {
-	BreakOnAbbreviations bool
-	Abbreviations        map[string]struct{} // pour recherche rapide
-	MergeShortPhrases    bool
+	Lang                 string              // langue du texte : choisit la liste intégrée (BuiltinAbbreviations)
+	BreakOnAbbreviations bool                // true : couper après toute abréviation (ancien comportement)
+	Abbreviations        map[string]struct{} // abréviations ajoutées à la liste intégrée ("dr", "z.b")
+	MergeShortPhrases    bool                // fusionner les phrases de moins de MinPhraseWordsToKeep mots
 	MinPhraseWordsToKeep int
+	Speakers             bool // repérer les changements de locuteur (tiret, >>, "NOM:", voir speakers.go)
 }
 
 // Filename compose le nom de fichier pour ce SubtitleDownload à partir de s.Title
@@ -119,8 +126,30 @@ func (sd *SubtitleDownload) ParseRawJSON3() (rawJSON3, error) {
 	if sd == nil {
 		return empty, fmt.Errorf("ParseRawJSON3: SubtitleDownload data est nil")
 	}
+	if sd.parsed != nil {
+		return *sd.parsed, nil
+	}
 	if len(sd.Data) == 0 {
 		return empty, fmt.Errorf("ParseRawJSON3: pas de données dans SubtitleDownload (nil/empty)")
 	}
 	return ParseJSON3Bytes(sd.Data)
 }
+
+// ParseRaw parse sd.Data selon sd.Track.Format (json3, srv3, vtt, ttml, srt).
+// Un format vide est traité comme json3. Un json3 déjà décodé en flux est retourné tel quel.
+func (sd *SubtitleDownload) ParseRaw() (rawJSON3, error) {
+	if sd == nil {
+		return rawJSON3{}, fmt.Errorf("ParseRaw: SubtitleDownload data est nil")
+	}
+	if sd.parsed != nil {
+		return *sd.parsed, nil
+	}
+	if len(sd.Data) == 0 {
+		return rawJSON3{}, fmt.Errorf("ParseRaw: pas de données dans SubtitleDownload (nil/empty)")
+	}
+	format := sd.Track.Format
+	if format == "" {
+		format = model.FormatJSON3
+	}
+	return ParseBytes(sd.Data, format)
+}
@@ -5,12 +5,42 @@ import (
 )
 
 // Phrase représente une phrase extraite des sous-titres,
-// avec son timestamp (début de la phrase) en millisecondes.
+// avec son timestamp (début de la phrase) et sa fin en millisecondes.
 type Phrase struct {
 	TimestampMs int64  // début de la phrase (ms depuis le début de la vidéo)
+	EndMs       int64  // fin de la phrase (ms) ; 0 si inconnue
 	Text        string // texte normalisé de la phrase
 	RuneCount   int    // nombre de runes unicode (len([]rune(Text)))
-	WordCount   int    // nombre de mots (strings.Fields)
+	WordCount   int    // nombre de mots selon la langue (voir CountWords)
+	Segment     string // catégorie SponsorBlock si la phrase est marquée (mode "tag"), sinon ""
+	Speaker     string // locuteur (étiquette "NOM:" ou S1/S2, voir speakers.go), "" si inconnu
+	// Words : timings par mot. Réels pour les captions auto (tOffsetMs),
+	// estimés au prorata des runes pour les sous-titres manuels. Peut être vide.
+	Words []Word
+}
+
+// Word : un mot et ses bornes en ms.
+type Word struct {
+	Text    string
+	StartMs int64
+	EndMs   int64
+}
+
+// DurationMs retourne la durée de la phrase (0 si la fin est inconnue).
+func (p Phrase) DurationMs() int64 {
+	if p.EndMs <= p.TimestampMs {
+		return 0
+	}
+	return p.EndMs - p.TimestampMs
+}
+
+// displayText retourne le texte à écrire dans les rendus : préfixé de la
+// catégorie SponsorBlock entre crochets quand la phrase est marquée.
+func (p Phrase) displayText() string {
+	if p.Segment == "" {
+		return p.Text
+	}
+	return "[" + p.Segment + "] " + p.Text
 }
 
 // Transcript représente le transcript résultant d'un traitement
@@ -18,8 +48,15 @@ type Phrase struct {
 type Transcript struct {
 	Title    string              // titre (hérité de SubtitleDownload)
 	Track    model.SubtitleTrack // métadonnée sur la piste (hérité de SubtitleDownload)
+	Reason   SelectReason        // raison du choix de la piste (hérité de SubtitleDownload)
 	Phrases  []Phrase            // phrases extraites et post-traitées
 	Chapters []model.Chapter
+	URL      string // URL de la vidéo, pour les liens horodatés (Markdown)
+	// ParagraphOpts : découpage en paragraphes des rendus txt et Markdown ;
+	// nil = une phrase par ligne (txt) et découpage historique (Markdown)
+	ParagraphOpts *ParagraphOptions
+	// ChapterSnap : recalage des chapitres sur les phrases (valeur zéro : la plus proche)
+	ChapterSnap ChapterSnap
 }
 
 // NewTranscript construit un Transcript à partir de données déjà prêtes.
@@ -32,3 +69,28 @@ func NewTranscript(title string, track model.SubtitleTrack, phrases []Phrase, ch
 		Chapters: chapters,
 	}
 }
+
+// TranslationNotice retourne un avertissement si la piste est une traduction
+// automatique de YouTube, "" sinon. Ajouté en tête du transcript sauvegardé et du prompt.
+func (t Transcript) TranslationNotice() string {
+	if !t.Track.Translated {
+		return ""
+	}
+	src := t.Track.SourceLang
+	if src == "" {
+		src = "?"
+	}
+	return "[Traduction automatique YouTube : " + src + " -> " + t.Track.Lang + ". Le texte peut contenir des erreurs.]"
+}
+
+// WithoutWords retourne une copie du transcript sans les timings par mot
+// (Phrases est copiée, l'original n'est pas modifié).
+func (t Transcript) WithoutWords() Transcript {
+	phrases := make([]Phrase, len(t.Phrases))
+	for i, p := range t.Phrases {
+		p.Words = nil
+		phrases[i] = p
+	}
+	t.Phrases = phrases
+	return t
+}
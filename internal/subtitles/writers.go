@@ -0,0 +1,242 @@
+package subtitles
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+// writers.go : rendus du transcript dans les formats de sortie (SaveAs).
+//   - SRT / WebVTT : une cue par phrase, fin = début de la phrase suivante (bornée) ;
+//   - JSON : phrases + chapitres + piste, pour un usage programmatique ;
+//   - Markdown : titres de chapitres et paragraphes précédés d'un lien horodaté.
+
+const (
+	// longueur de ligne visée dans les cues SRT/VTT (convention sous-titrage)
+	cueLineRunes = 42
+)
+
+// Render retourne le transcript au format demandé (SaveTranscript, SaveAs).
+// txt : notice de traduction puis Text() (paragraphes, dialogue ou une phrase
+// par ligne avec chapitres) ; PlainNoChapters donne le texte brut.
+func (t Transcript) Render(format model.Format) ([]byte, error) {
+	switch format {
+	case model.FormatTXT:
+		text := t.Text()
+		if notice := t.TranslationNotice(); notice != "" {
+			text = notice + "\n\n" + text
+		}
+		return []byte(text), nil
+	case model.FormatMARKDOWN:
+		return []byte(t.Markdown()), nil
+	case model.FormatSRT:
+		return []byte(t.SRT()), nil
+	case model.FormatVTT:
+		return []byte(t.VTT()), nil
+	case model.FormatJSON:
+		return t.JSON()
+	default:
+		return nil, fmt.Errorf("format de transcript non supporté: %s", format)
+	}
+}
+
+// Text retourne le texte du transcript txt : paragraphes (ParagraphOpts),
+// dialogue si des locuteurs sont connus, sinon une phrase par ligne.
+func (t Transcript) Text() string {
+	switch {
+	case t.ParagraphOpts != nil:
+		return t.Paragraphs(*t.ParagraphOpts)
+	case t.HasSpeakers():
+		return t.Dialogue()
+	}
+	return t.Plain()
+}
+
+// sortedPhrases retourne une copie triée des phrases (l'entrée n'est pas modifiée).
+func (t Transcript) sortedPhrases() []Phrase {
+	phrases := make([]Phrase, len(t.Phrases))
+	copy(phrases, t.Phrases)
+	ensureSortedPhrases(phrases)
+	return phrases
+}
+
+// SRT retourne le transcript au format SubRip.
+func (t Transcript) SRT() string {
+	return t.cues(false)
+}
+
+// VTT retourne le transcript au format WebVTT.
+func (t Transcript) VTT() string {
+	return t.cues(true)
+}
+
+func (t Transcript) cues(vtt bool) string {
+	phrases := t.sortedPhrases()
+	ends := phraseEnds(phrases)
+	var b strings.Builder
+	if vtt {
+		b.WriteString("WEBVTT\n\n")
+		if notice := t.TranslationNotice(); notice != "" {
+			fmt.Fprintf(&b, "NOTE %s\n\n", notice)
+		}
+	}
+	n := 0
+	speaker := "" // locuteur de la cue précédente
+	for i, p := range phrases {
+		text := strings.TrimSpace(p.displayText())
+		if text == "" {
+			continue
+		}
+		n++
+		if !vtt {
+			fmt.Fprintf(&b, "%d\n", n)
+		}
+		fmt.Fprintf(&b, "%s --> %s\n", cueTimestamp(p.TimestampMs, vtt), cueTimestamp(ends[i], vtt))
+		// locuteur : balise de voix WebVTT, étiquette "Nom:" en SRT à chaque changement
+		switch {
+		case p.Speaker != "" && vtt:
+			text = "<v " + p.Speaker + ">" + text
+		case p.Speaker != "" && p.Speaker != speaker:
+			text = p.Speaker + ": " + text
+		}
+		speaker = p.Speaker
+		b.WriteString(wrapCueText(text, cueLineRunes))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// cueTimestamp : "hh:mm:ss,mmm" (SRT) ou "hh:mm:ss.mmm" (VTT).
+func cueTimestamp(ms int64, vtt bool) string {
+	ms = max(ms, 0)
+	sep := ","
+	if vtt {
+		sep = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, sep, ms%1000)
+}
+
+// wrapCueText découpe text en lignes d'environ width runes, sur les espaces.
+func wrapCueText(text string, width int) string {
+	var lines []string
+	var cur strings.Builder
+	curLen := 0
+	for _, w := range strings.Fields(text) {
+		wl := len([]rune(w))
+		if curLen > 0 && curLen+1+wl > width {
+			lines = append(lines, cur.String())
+			cur.Reset()
+			curLen = 0
+		}
+		if curLen > 0 {
+			cur.WriteByte(' ')
+			curLen++
+		}
+		cur.WriteString(w)
+		curLen += wl
+	}
+	if cur.Len() > 0 {
+		lines = append(lines, cur.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// transcriptJSON : forme exportée par JSON (pas l'URL signée de la piste).
+type transcriptJSON struct {
+	Title      string          `json:"title"`
+	URL        string          `json:"url,omitempty"`
+	Lang       string          `json:"lang,omitempty"`
+	Source     model.SubSource `json:"source,omitempty"`
+	Reason     SelectReason    `json:"reason,omitempty"`
+	Translated bool            `json:"translated,omitempty"`
+	SourceLang string          `json:"source_lang,omitempty"`
+	Chapters   []model.Chapter `json:"chapters,omitempty"`
+	Phrases    []phraseJSON    `json:"phrases"`
+}
+
+type phraseJSON struct {
+	StartMs int64      `json:"start_ms"`
+	EndMs   int64      `json:"end_ms"`
+	Text    string     `json:"text"`
+	Segment string     `json:"segment,omitempty"`
+	Speaker string     `json:"speaker,omitempty"`
+	Words   []wordJSON `json:"words,omitempty"`
+}
+
+type wordJSON struct {
+	Text    string `json:"text"`
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+}
+
+// JSON retourne les phrases (début/fin en ms, mots si présents) et les chapitres, indentés.
+func (t Transcript) JSON() ([]byte, error) {
+	phrases := t.sortedPhrases()
+	ends := phraseEnds(phrases)
+	out := transcriptJSON{
+		Title:      t.Title,
+		URL:        t.URL,
+		Lang:       t.Track.Lang,
+		Source:     t.Track.Source,
+		Reason:     t.Reason,
+		Translated: t.Track.Translated,
+		SourceLang: t.Track.SourceLang,
+		Chapters:   t.Chapters,
+		Phrases:    make([]phraseJSON, 0, len(phrases)),
+	}
+	for i, p := range phrases {
+		pj := phraseJSON{
+			StartMs: p.TimestampMs,
+			EndMs:   ends[i],
+			Text:    p.Text,
+			Segment: p.Segment,
+			Speaker: p.Speaker,
+		}
+		for _, w := range p.Words {
+			pj.Words = append(pj.Words, wordJSON(w))
+		}
+		out.Phrases = append(out.Phrases, pj)
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("transcript json: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// Markdown retourne le transcript en Markdown : titre, avertissement de traduction,
+// titres de chapitres (##) et paragraphes commençant par un lien [hh:mm:ss](url&t=Ns).
+// Les chapitres sont placés comme dans Plain (transcriptEvents, ChapterSnap), les
+// paragraphes découpés selon ParagraphOpts (markdownParagraphs si nil), préfixés
+// du locuteur en gras s'il est connu.
+func (t Transcript) Markdown() string {
+	var b strings.Builder
+	if title := strings.TrimSpace(t.Title); title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", title)
+	}
+	if notice := t.TranslationNotice(); notice != "" {
+		fmt.Fprintf(&b, "> %s\n\n", notice)
+	}
+	if len(t.Phrases) == 0 {
+		return b.String()
+	}
+
+	opts := markdownParagraphs
+	if t.ParagraphOpts != nil {
+		opts = *t.ParagraphOpts
+	}
+	for _, bl := range paragraphBlocks(t.transcriptEvents(t.ChapterSnap), opts) {
+		if bl.isChapter {
+			fmt.Fprintf(&b, "## %s\n\n", bl.text)
+			continue
+		}
+		if bl.speaker != "" {
+			fmt.Fprintf(&b, "%s **%s** : %s\n\n", timestampLink(bl.ts, t.URL), bl.speaker, bl.text)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s\n\n", timestampLink(bl.ts, t.URL), bl.text)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
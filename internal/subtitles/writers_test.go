@@ -0,0 +1,88 @@
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/patrickprogramme/subscribe/pkg/model"
+)
+
+func writersTranscript() Transcript {
+	return Transcript{
+		Title: "Demo",
+		URL:   "https://www.youtube.com/watch?v=x",
+		Phrases: []Phrase{
+			{TimestampMs: 1000, Text: "Hello there."},
+			{TimestampMs: 3500, Text: "Second phrase."},
+			{TimestampMs: 62000, Text: "After the pause."},
+		},
+		Chapters: []model.Chapter{{Start: 60, Title: "Part two"}},
+	}
+}
+
+func TestTranscriptCues(t *testing.T) {
+	tr := writersTranscript()
+
+	srt := tr.SRT()
+	wantSRT := "1\n00:00:01,000 --> 00:00:03,500\nHello there.\n\n" +
+		"2\n00:00:03,500 --> 00:00:18,500\nSecond phrase.\n\n" +
+		"3\n00:01:02,000 --> 00:01:07,000\nAfter the pause.\n\n"
+	if srt != wantSRT {
+		t.Errorf("SRT:\n%s\nwant:\n%s", srt, wantSRT)
+	}
+
+	vtt := tr.VTT()
+	if !strings.HasPrefix(vtt, "WEBVTT\n\n00:00:01.000 --> 00:00:03.500\nHello there.\n") {
+		t.Errorf("VTT inattendu:\n%s", vtt)
+	}
+	// le VTT produit doit être relu par notre propre parser
+	raw, err := ParseVTTBytes([]byte(vtt))
+	if err != nil || len(raw.Events) != 3 {
+		t.Errorf("relecture VTT: %d events, err=%v", len(raw.Events), err)
+	}
+}
+
+func TestTranscriptMarkdown(t *testing.T) {
+	md := writersTranscript().Markdown()
+	want := "# Demo\n\n" +
+		"[00:00:01](https://www.youtube.com/watch?v=x&t=1s) Hello there. Second phrase.\n\n" +
+		"## Part two\n\n" +
+		"[00:01:02](https://www.youtube.com/watch?v=x&t=62s) After the pause.\n"
+	if md != want {
+		t.Errorf("Markdown:\n%s\nwant:\n%s", md, want)
+	}
+}
+
+func TestSaveAsTXT(t *testing.T) {
+	// SaveAs txt = Render txt (chapitres compris) ; PlainNoChapters reste une phrase par ligne
+	tr := writersTranscript()
+	path := filepath.Join(t.TempDir(), "demo.txt")
+	if err := tr.SaveAs(path, model.FormatTXT); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := tr.Render(model.FormatTXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) || !strings.Contains(string(got), "## Part two") {
+		t.Errorf("SaveAs = %q; want Render = %q", got, want)
+	}
+	if plain, want := tr.PlainNoChapters(), "Hello there.\nSecond phrase.\nAfter the pause.\n"; plain != want {
+		t.Errorf("PlainNoChapters = %q; want %q", plain, want)
+	}
+}
+
+func TestWrapCueText(t *testing.T) {
+	got := wrapCueText("one two three four five six seven eight nine ten eleven", 20)
+	for _, line := range strings.Split(got, "\n") {
+		if len(line) > 20 {
+			t.Errorf("ligne trop longue: %q", line)
+		}
+	}
+}
@@ -15,6 +15,8 @@ type Interface interface {
 
 	PrintInfo(ctx context.Context, s string)
 	PrintError(ctx context.Context, s string)
+	// PrintProgress affiche une ligne de progression (ex: stderr de yt-dlp en direct).
+	PrintProgress(ctx context.Context, s string)
 
 	WaitForUserToCopyResponse(ctx context.Context) (bool, error)
 	// GetClipboardChoice interagit avec l'utilisateur et retourne:
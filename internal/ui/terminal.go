@@ -73,6 +73,10 @@ func (t *terminalUI) PrintError(ctx context.Context, s string) {
 	fmt.Fprintln(os.Stderr, s)
 }
 
+func (t *terminalUI) PrintProgress(ctx context.Context, s string) {
+	fmt.Fprintf(os.Stderr, "  › %s\n", s)
+}
+
 // GetClipboardChoice propose d'utiliser le texte du presse-papier.
 // Retourne (content, choice, err).
 // - content : texte provenant du clipboard (vide si choice != ChoiceUse).
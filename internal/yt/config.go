@@ -1,5 +1,10 @@
 package yt
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // YtDlpConfig représente les flags ajoutables quand on utilise yt-dlp
 type YtDlpConfig struct {
 	SkipDownload bool
@@ -7,6 +12,13 @@ type YtDlpConfig struct {
 	NoProgress   bool
 	NoUpdate     bool
 	NoConfig     bool // true => ajouter --no-config pour ignorer les configs utilisateur
+	Verbose      bool // true => ajouter --verbose (lignes [debug] sur stderr)
+
+	// Commentaires : WriteComments => --write-comments + extractor-args youtube
+	WriteComments   bool
+	CommentLimit    int    // nombre max de commentaires (<=0 => tous)
+	CommentSort     string // "top" ou "new"
+	CommentMaxDepth int    // 1 = premier niveau uniquement (<=0 => tous)
 }
 
 // NewYtDlpConfig initalise une configuration standard de yt-dlp, showWarning vient du yaml de config
@@ -40,6 +52,30 @@ func (c *YtDlpConfig) BuildArgs(url string) []string {
 	if c.NoUpdate {
 		args = append(args, "--no-update")
 	}
+	if c.Verbose {
+		args = append(args, "--verbose")
+	}
+	if c.WriteComments {
+		args = append(args, "--write-comments", "--extractor-args", c.commentExtractorArgs())
+	}
 	args = append(args, url)
 	return args
 }
+
+// commentExtractorArgs construit la valeur de --extractor-args pour les commentaires.
+// max_comments = max-comments,max-parents,max-replies,max-replies-per-thread,max-depth
+func (c *YtDlpConfig) commentExtractorArgs() string {
+	limit := "all"
+	if c.CommentLimit > 0 {
+		limit = strconv.Itoa(c.CommentLimit)
+	}
+	depth := "all"
+	if c.CommentMaxDepth > 0 {
+		depth = strconv.Itoa(c.CommentMaxDepth)
+	}
+	sortBy := "top"
+	if c.CommentSort == "new" {
+		sortBy = "new"
+	}
+	return fmt.Sprintf("youtube:max_comments=%s,all,all,all,%s;comment_sort=%s", limit, depth, sortBy)
+}
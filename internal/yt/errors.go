@@ -0,0 +1,215 @@
+package yt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrorKind classe les échecs de yt-dlp selon leur cause probable.
+// La classe détermine le message de remède affiché et la politique de retry.
+type ErrorKind int
+
+const (
+	KindUnknown         ErrorKind = iota
+	KindPrivate                   // vidéo privée
+	KindMembersOnly               // réservée aux membres de la chaîne
+	KindSignIn                    // limite d'âge / connexion requise
+	KindGeoBlocked                // indisponible dans le pays
+	KindRemoved                   // supprimée / compte fermé
+	KindThrottled                 // HTTP 429
+	KindNetwork                   // réseau indisponible
+	KindExtractorBroken           // extracteur cassé (yt-dlp obsolète)
+)
+
+// Erreurs sentinelles : utilisables avec errors.Is sur une *ExtractError.
+var (
+	ErrPrivateVideo    = errors.New("vidéo privée")
+	ErrMembersOnly     = errors.New("vidéo réservée aux membres")
+	ErrSignInRequired  = errors.New("connexion requise (limite d'âge ou vérification)")
+	ErrGeoBlocked      = errors.New("vidéo bloquée dans votre pays")
+	ErrRemoved         = errors.New("vidéo supprimée ou indisponible")
+	ErrThrottled       = errors.New("trop de requêtes (HTTP 429)")
+	ErrNetwork         = errors.New("réseau indisponible")
+	ErrExtractorBroken = errors.New("extracteur yt-dlp en échec")
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindPrivate:
+		return "private"
+	case KindMembersOnly:
+		return "members-only"
+	case KindSignIn:
+		return "sign-in"
+	case KindGeoBlocked:
+		return "geo-blocked"
+	case KindRemoved:
+		return "removed"
+	case KindThrottled:
+		return "throttled"
+	case KindNetwork:
+		return "network"
+	case KindExtractorBroken:
+		return "extractor-broken"
+	default:
+		return "unknown"
+	}
+}
+
+// sentinel retourne l'erreur sentinelle associée à la classe (nil pour KindUnknown).
+func (k ErrorKind) sentinel() error {
+	switch k {
+	case KindPrivate:
+		return ErrPrivateVideo
+	case KindMembersOnly:
+		return ErrMembersOnly
+	case KindSignIn:
+		return ErrSignInRequired
+	case KindGeoBlocked:
+		return ErrGeoBlocked
+	case KindRemoved:
+		return ErrRemoved
+	case KindThrottled:
+		return ErrThrottled
+	case KindNetwork:
+		return ErrNetwork
+	case KindExtractorBroken:
+		return ErrExtractorBroken
+	default:
+		return nil
+	}
+}
+
+// Transient indique si la classe d'erreur peut disparaître en réessayant.
+// Seules ces classes sont soumises au retry avec backoff.
+func (k ErrorKind) Transient() bool {
+	return k == KindThrottled || k == KindNetwork
+}
+
+// Remedy retourne un conseil destiné à l'utilisateur pour la classe d'erreur.
+func (k ErrorKind) Remedy() string {
+	switch k {
+	case KindPrivate:
+		return "La vidéo est privée : demandez l'accès au propriétaire ou utilisez une autre vidéo."
+	case KindMembersOnly:
+		return "La vidéo est réservée aux membres de la chaîne : un abonnement (et des cookies de session) sont nécessaires."
+	case KindSignIn:
+		return "YouTube exige une connexion (limite d'âge ou vérification anti-robot) : configurez des cookies pour yt-dlp."
+	case KindGeoBlocked:
+		return "La vidéo n'est pas disponible dans votre pays : essayez depuis une autre région (VPN/proxy)."
+	case KindRemoved:
+		return "La vidéo a été supprimée ou le compte fermé : inutile de réessayer."
+	case KindThrottled:
+		return "YouTube limite les requêtes (HTTP 429) : patientez quelques minutes avant de relancer."
+	case KindNetwork:
+		return "Le réseau semble indisponible : vérifiez votre connexion internet."
+	case KindExtractorBroken:
+		return "L'extracteur de yt-dlp a échoué, il est probablement obsolète : mettez yt-dlp à jour."
+	default:
+		return "Consultez la sortie de yt-dlp ci-dessus pour plus de détails."
+	}
+}
+
+// ExtractError est l'erreur typée renvoyée quand yt-dlp échoue.
+// - Kind   : classe de l'erreur (voir Classify)
+// - Detail : ligne "ERROR:" la plus pertinente de la sortie de yt-dlp
+// - Output : sortie brute de yt-dlp (stderr), utile pour le diagnostic
+// - Err    : erreur d'exécution sous-jacente (exit status, ...)
+type ExtractError struct {
+	Kind   ErrorKind
+	Detail string
+	Output string
+	Err    error
+}
+
+func (e *ExtractError) Error() string {
+	msg := "yt-dlp a échoué"
+	if s := e.Kind.sentinel(); s != nil {
+		msg = fmt.Sprintf("%s : %v", msg, s)
+	}
+	if e.Detail != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Detail)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *ExtractError) Unwrap() error { return e.Err }
+
+// Is permet errors.Is(err, ErrPrivateVideo) etc.
+func (e *ExtractError) Is(target error) bool {
+	s := e.Kind.sentinel()
+	return s != nil && s == target
+}
+
+// Remedy raccourci vers e.Kind.Remedy().
+func (e *ExtractError) Remedy() string { return e.Kind.Remedy() }
+
+// Transient raccourci vers e.Kind.Transient().
+func (e *ExtractError) Transient() bool { return e.Kind.Transient() }
+
+// classifyRule associe un motif de la sortie de yt-dlp à une classe.
+// L'ordre compte : les classes les plus spécifiques sont testées en premier
+// (ex: "Private video" contient aussi "Sign in").
+type classifyRule struct {
+	kind ErrorKind
+	re   *regexp.Regexp
+}
+
+var classifyRules = []classifyRule{
+	{KindPrivate, regexp.MustCompile(`(?i)private video`)},
+	{KindMembersOnly, regexp.MustCompile(`(?i)members[- ]only|join this channel|available to this channel's members`)},
+	{KindSignIn, regexp.MustCompile(`(?i)confirm your age|age[- ]restricted|inappropriate for some users|sign in to confirm|login required|requires? (authentication|login)|use --cookies`)},
+	{KindGeoBlocked, regexp.MustCompile(`(?i)not (made this video )?available in your country|geo[- ]?restrict|blocked it in your country|from your location`)},
+	{KindRemoved, regexp.MustCompile(`(?i)has been removed|been terminated|no longer available|video unavailable|does not exist|video is unavailable|HTTP Error 404`)},
+	{KindThrottled, regexp.MustCompile(`(?i)HTTP Error 429|too many requests|rate[- ]limit`)},
+	{KindNetwork, regexp.MustCompile(`(?i)urlopen error|name resolution|getaddrinfo|failed to resolve|network is unreachable|connection (refused|reset|aborted)|timed out|no route to host|unable to connect|ssl: |remote end closed connection`)},
+	{KindExtractorBroken, regexp.MustCompile(`(?i)unable to extract|please report this issue|confirm you are on the latest version|signature extraction failed|nsig extraction failed|unsupported url|failed to parse json`)},
+}
+
+// Classify déduit la classe d'erreur depuis la sortie (stderr) de yt-dlp.
+// Seules les lignes "ERROR:" sont examinées, de la dernière à la première : un
+// WARNING (ex: "nsig extraction failed") ne doit pas classer une erreur sans rapport.
+// Retourne aussi la ligne "ERROR:" la plus pertinente (vide si aucune).
+func Classify(output string) (ErrorKind, string) {
+	lines := errorLines(output)
+	if len(lines) == 0 {
+		return KindUnknown, ""
+	}
+	detail := lines[len(lines)-1]
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, r := range classifyRules {
+			if r.re.MatchString(lines[i]) {
+				return r.kind, lines[i]
+			}
+		}
+	}
+	return KindUnknown, detail
+}
+
+// errorLines retourne les lignes commençant par "ERROR:" (sans le préfixe), dans l'ordre.
+func errorLines(output string) []string {
+	var out []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "ERROR:"); ok {
+			out = append(out, strings.TrimSpace(rest))
+		}
+	}
+	return out
+}
+
+// newExtractError construit une *ExtractError classée depuis la sortie de yt-dlp.
+func newExtractError(output string, err error) *ExtractError {
+	kind, detail := Classify(output)
+	return &ExtractError{
+		Kind:   kind,
+		Detail: detail,
+		Output: output,
+		Err:    err,
+	}
+}
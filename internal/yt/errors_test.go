@@ -0,0 +1,134 @@
+package yt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   ErrorKind
+	}{
+		{"private", "ERROR: [youtube] abc: Private video. Sign in if you've been granted access to this video", KindPrivate},
+		{"members", "ERROR: [youtube] abc: Join this channel to get access to members-only content like this video", KindMembersOnly},
+		{"age", "ERROR: [youtube] abc: Sign in to confirm your age. This video may be inappropriate for some users.", KindSignIn},
+		{"bot check", "ERROR: [youtube] abc: Sign in to confirm you’re not a bot. Use --cookies-from-browser", KindSignIn},
+		{"geo", "ERROR: [youtube] abc: The uploader has not made this video available in your country", KindGeoBlocked},
+		{"removed", "ERROR: [youtube] abc: Video unavailable. This video has been removed by the uploader", KindRemoved},
+		{"429", "ERROR: Unable to download API page: HTTP Error 429: Too Many Requests", KindThrottled},
+		{"network", "ERROR: [youtube] abc: Unable to download webpage: <urlopen error [Errno -3] Temporary failure in name resolution>", KindNetwork},
+		{"extractor", "ERROR: [youtube] abc: Unable to extract initial player response; please report this issue on https://github.com/yt-dlp/yt-dlp/issues", KindExtractorBroken},
+		{"unknown", "ERROR: something odd happened", KindUnknown},
+		{"warning then error", "WARNING: [youtube] nsig extraction failed\nERROR: [youtube] abc: Private video", KindPrivate},
+		{"warning ignored", "WARNING: [youtube] nsig extraction failed\nERROR: something odd happened", KindUnknown},
+		{"earlier error line", "ERROR: HTTP Error 429: Too Many Requests\nERROR: giving up", KindThrottled},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := Classify(tc.output)
+			if got != tc.want {
+				t.Fatalf("Classify = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractErrorIs(t *testing.T) {
+	err := newExtractError("ERROR: [youtube] abc: Private video", errors.New("exit status 1"))
+	if !errors.Is(err, ErrPrivateVideo) {
+		t.Fatalf("expected errors.Is(err, ErrPrivateVideo), got %v", err)
+	}
+	if errors.Is(err, ErrRemoved) {
+		t.Fatalf("unexpected match with ErrRemoved")
+	}
+	if err.Transient() {
+		t.Fatalf("private video must not be transient")
+	}
+}
+
+// fakeClient renvoie les erreurs de errs dans l'ordre, puis un succès.
+type fakeClient struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeClient) CheckBinary() error                             { return nil }
+func (f *fakeClient) GetVersion(ctx context.Context) (string, error) { return "test", nil }
+func (f *fakeClient) ExtractRaw(ctx context.Context, url string) (*ExtractedRaw, error) {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return nil, f.errs[f.calls-1]
+	}
+	return &ExtractedRaw{JSON: []byte(`{}`)}, nil
+}
+
+// hangClient bloque les `hangs` premiers appels jusqu'à l'expiration du ctx.
+type hangClient struct {
+	hangs int
+	calls int
+}
+
+func (h *hangClient) CheckBinary() error                             { return nil }
+func (h *hangClient) GetVersion(ctx context.Context) (string, error) { return "test", nil }
+func (h *hangClient) ExtractRaw(ctx context.Context, url string) (*ExtractedRaw, error) {
+	h.calls++
+	if h.calls <= h.hangs {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &ExtractedRaw{JSON: []byte(`{}`)}, nil
+}
+
+func TestExtractRawWithRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 2}
+	throttled := newExtractError("ERROR: HTTP Error 429: Too Many Requests", nil)
+	removed := newExtractError("ERROR: [youtube] abc: This video has been removed", nil)
+
+	t.Run("transient retried", func(t *testing.T) {
+		c := &fakeClient{errs: []error{throttled, throttled}}
+		if _, err := ExtractRawWithRetry(context.Background(), c, "u", policy, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.calls != 3 {
+			t.Fatalf("calls = %d; want 3", c.calls)
+		}
+	})
+
+	t.Run("permanent not retried", func(t *testing.T) {
+		c := &fakeClient{errs: []error{removed}}
+		_, err := ExtractRawWithRetry(context.Background(), c, "u", policy, nil)
+		if !errors.Is(err, ErrRemoved) {
+			t.Fatalf("err = %v; want ErrRemoved", err)
+		}
+		if c.calls != 1 {
+			t.Fatalf("calls = %d; want 1", c.calls)
+		}
+	})
+
+	t.Run("each attempt has its own timeout", func(t *testing.T) {
+		p := policy
+		p.AttemptTimeout = 20 * time.Millisecond
+		c := &hangClient{hangs: 2}
+		if _, err := ExtractRawWithRetry(context.Background(), c, "u", p, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.calls != 3 {
+			t.Fatalf("calls = %d; want 3", c.calls)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		c := &fakeClient{errs: []error{throttled, throttled, throttled, throttled}}
+		_, err := ExtractRawWithRetry(context.Background(), c, "u", policy, nil)
+		if !errors.Is(err, ErrThrottled) {
+			t.Fatalf("err = %v; want ErrThrottled", err)
+		}
+		if c.calls != 3 {
+			t.Fatalf("calls = %d; want 3", c.calls)
+		}
+	})
+}
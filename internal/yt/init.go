@@ -11,10 +11,17 @@ import (
 const defaultVersionTimeout = 5 * time.Second
 
 // InitYtDlp initialise le client YtDlp, vérifie le binaire et récupère la version.
+// onMessage (optionnel) reçoit en direct les lignes de stderr de yt-dlp.
 // Retourne le client (implémentant Interface) et la version.
-func InitYtDlp(ctx context.Context, cfg *config.Config) (Interface, string, error) {
+func InitYtDlp(ctx context.Context, cfg *config.Config, onMessage func(Message)) (Interface, string, error) {
 	ytDlpcfg := NewYtDlpConfig(cfg.YtDlp.ShowWarnings)
+	ytDlpcfg.Verbose = cfg.YtDlp.Verbose
+	ytDlpcfg.WriteComments = cfg.Comments.Enabled
+	ytDlpcfg.CommentLimit = cfg.Comments.Limit
+	ytDlpcfg.CommentSort = cfg.Comments.Sort
+	ytDlpcfg.CommentMaxDepth = cfg.Comments.MaxDepth
 	dl := NewYtDlp(cfg.YtDlp.Name, cfg.YtDlp.ResolvedPath, *ytDlpcfg)
+	dl.OnMessage = onMessage
 	dl.ShowPath()
 
 	// vérifier la présence du binaire
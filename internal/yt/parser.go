@@ -3,7 +3,9 @@ package yt
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +28,8 @@ func ParseYTDLP(raw []byte) (*model.Meta, error) {
 		Categories:  y.Categories,
 		YtTags:      y.YtTags,
 		Description: y.Description,
+		Language:    y.Language,
+		Duration:    model.Seconds(int64(math.Round(y.Duration))),
 	}
 
 	// upload_date: try YYYYMMDD puis timestamp (fallback)
@@ -46,69 +50,173 @@ func ParseYTDLP(raw []byte) (*model.Meta, error) {
 		}
 		meta.Chapters = append(meta.Chapters, model.Chapter{
 			Start: model.Seconds(int64(math.Round(start))),
+			End:   model.Seconds(int64(math.Round(c.EndTime))),
 			Title: c.Title,
 		})
 	}
+	// yt-dlp ne relève pas toujours les timestamps de la description
+	if len(meta.Chapters) == 0 {
+		meta.Chapters = model.ParseDescriptionChapters(y.Description)
+	}
+	model.FillChapterEnds(meta.Chapters, meta.Duration)
+
+	// heatmap "les plus revus" (absent pour les vidéos peu vues)
+	for _, h := range y.Heatmap {
+		meta.Heatmap = append(meta.Heatmap, model.HeatmapPoint{
+			StartMs: int64(math.Round(h.StartTime * 1000)),
+			EndMs:   int64(math.Round(h.EndTime * 1000)),
+			Value:   h.Value,
+		})
+	}
+
+	// commentaires (uniquement si yt-dlp a été lancé avec --write-comments)
+	meta.Comments = parseComments(y.Comments)
 
-	// sous-titres manuels : on garde tout ce qui est au bon format
-	manual := selectManualSubs(y.Subtitles, model.FormatJSON3)
+	// sous-titres manuels : une piste par langue, au meilleur format disponible
+	// (json3 > srv3 > vtt > ttml > srt : json3 peut manquer, surtout hors YouTube)
+	prefs := model.SubtitleFormatPreference
+	manual := selectManualSubs(y.Subtitles, prefs)
 	if len(manual) > 0 {
 		meta.ManualSubs = append(meta.ManualSubs, manual...)
 	}
 
-	// sous-titres automatiques : on garde uniquement : bon format + orig
-	auto := selectCaptionOriginal(y.AutomaticCaptions, model.FormatJSON3)
+	// sous-titres automatiques : on garde uniquement : meilleur format + orig
+	auto := selectCaptionOriginal(y.AutomaticCaptions, prefs)
 	if len(auto) > 0 {
 		meta.AutoSubs = append(meta.AutoSubs, auto...)
 	}
 
+	// traductions automatiques : listées à part, la langue source est celle de la piste -orig
+	sourceLang := meta.Language
+	if len(auto) > 0 {
+		sourceLang = auto[0].Lang
+	}
+	meta.TranslatedSubs = selectCaptionTranslated(y.AutomaticCaptions, prefs, sourceLang)
+
 	return meta, nil
 }
 
+// sortedLangs retourne les clés de langue triées : l'itération sur une map Go
+// est aléatoire, et la piste choisie doit être déterministe.
+func sortedLangs(m map[string][]subtitleItem) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// bestItem retourne l'item du meilleur format disponible selon prefs
+// (ex: json3 > srv3 > vtt > ttml > srt). false si aucun format n'est accepté.
+func bestItem(items []subtitleItem, prefs []model.Format) (subtitleItem, model.Format, bool) {
+	best, bestRank := subtitleItem{}, len(prefs)
+	var bestFormat model.Format
+	for _, it := range items {
+		if it.URL == "" {
+			continue
+		}
+		pf, err := model.ParseFormat(it.Ext)
+		if err != nil {
+			continue
+		}
+		if rank := slices.Index(prefs, pf); rank >= 0 && rank < bestRank {
+			best, bestRank, bestFormat = it, rank, pf
+		}
+	}
+	return best, bestFormat, bestRank < len(prefs)
+}
+
 // selectCaptionOriginal parcourt la map `auto` (automatic_captions) et renvoie
-// toutes les pistes dont la clé langue se termine par "-orig" et dont le format
-// correspond au paramètre `format`.
-func selectCaptionOriginal(auto map[string][]subtitleItem, format model.Format) []model.SubtitleTrack {
+// une piste par langue dont la clé se termine par "-orig", au meilleur format
+// disponible selon `prefs`. Le suffixe "-orig" est retiré de Lang.
+func selectCaptionOriginal(auto map[string][]subtitleItem, prefs []model.Format) []model.SubtitleTrack {
 	var out []model.SubtitleTrack
-	for lang, tracks := range auto {
+	for _, lang := range sortedLangs(auto) {
 		// on ne veut que les langues originales : -orig
 		if !strings.HasSuffix(lang, suffix) {
 			continue
 		}
+		if it, pf, ok := bestItem(auto[lang], prefs); ok {
+			out = append(out, model.SubtitleTrack{
+				Lang:   strings.TrimSuffix(lang, suffix),
+				Format: pf,
+				URL:    it.URL,
+				Source: model.SubSourceAutomatic,
+			})
+		}
+	}
+	return out
+}
 
-		// langClean := strings.TrimSuffix(lang, suffix)
-		langClean := lang // temporaire, décommente au dessus et supprime cette ligne
-		for _, it := range tracks {
-			// ne garde qu'un format
-			if pf, err := model.ParseFormat(it.Ext); err == nil && pf == format {
-				st := model.SubtitleTrack{
-					Lang:   langClean,
-					Format: pf,
-					URL:    it.URL,
-					Source: model.SubSourceAutomatic,
-				}
-				out = append(out, st)
-			}
+// selectCaptionTranslated renvoie les traductions automatiques de YouTube :
+// les clés sans suffixe "-orig", hors langue d'origine elle-même.
+// sourceLang est la langue de la piste originale (vide si inconnue).
+func selectCaptionTranslated(auto map[string][]subtitleItem, prefs []model.Format, sourceLang string) []model.SubtitleTrack {
+	var out []model.SubtitleTrack
+	for _, lang := range sortedLangs(auto) {
+		if strings.HasSuffix(lang, suffix) {
+			continue
+		}
+		if sourceLang != "" && model.BaseLang(lang) == model.BaseLang(sourceLang) {
+			continue
+		}
+		if it, pf, ok := bestItem(auto[lang], prefs); ok {
+			out = append(out, model.SubtitleTrack{
+				Lang:       lang,
+				Format:     pf,
+				URL:        it.URL,
+				Source:     model.SubSourceAutomatic,
+				Translated: true,
+				SourceLang: sourceLang,
+			})
 		}
 	}
 	return out
 }
 
-// selectManualSubs récupère tous les sous-titres manuels
-func selectManualSubs(manual map[string][]subtitleItem, format model.Format) []model.SubtitleTrack {
+// selectManualSubs récupère une piste manuelle par langue, au meilleur format selon `prefs`.
+func selectManualSubs(manual map[string][]subtitleItem, prefs []model.Format) []model.SubtitleTrack {
 	var out []model.SubtitleTrack
-	for lang, tracks := range manual {
-		for _, it := range tracks {
-			if pf, err := model.ParseFormat(it.Ext); err == nil && pf == format {
-				out = append(out, model.SubtitleTrack{
-					Lang:   lang,
-					Format: pf,
-					URL:    it.URL,
-					Source: model.SubSourceManual,
-				})
-			}
+	for _, lang := range sortedLangs(manual) {
+		if it, pf, ok := bestItem(manual[lang], prefs); ok {
+			out = append(out, model.SubtitleTrack{
+				Lang:   lang,
+				Format: pf,
+				URL:    it.URL,
+				Source: model.SubSourceManual,
+			})
 		}
 	}
 
 	return out
 }
+
+// parseComments convertit les commentaires bruts de yt-dlp en []model.Comment.
+// Les timestamps cités dans le texte (ex: "à 12:34") sont extraits dans Mentions.
+func parseComments(raw []ytdlpComment) []model.Comment {
+	if len(raw) == 0 {
+		return nil
+	}
+	out := make([]model.Comment, 0, len(raw))
+	for _, c := range raw {
+		text := strings.TrimSpace(c.Text)
+		if text == "" {
+			continue
+		}
+		parent := c.Parent
+		if parent == "root" {
+			parent = ""
+		}
+		mc := model.Comment{
+			ID:         c.ID,
+			ParentID:   parent,
+			Author:     c.Author,
+			Text:       text,
+			Likes:      c.LikeCount,
+			Pinned:     c.IsPinned,
+			ByUploader: c.AuthorIsUploader,
+			Mentions:   model.FindTimestamps(text),
+		}
+		if c.Timestamp != 0 {
+			mc.Timestamp = time.Unix(c.Timestamp, 0).UTC()
+		}
+		out = append(out, mc)
+	}
+	return out
+}
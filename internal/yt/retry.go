@@ -0,0 +1,124 @@
+package yt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy décrit le retry avec backoff exponentiel appliqué à ExtractRaw.
+// Seules les erreurs transitoires (voir ErrorKind.Transient) sont réessayées :
+// une vidéo privée ou supprimée échoue immédiatement.
+type RetryPolicy struct {
+	MaxAttempts  int           // nombre total de tentatives (1 = pas de retry)
+	InitialDelay time.Duration // délai avant la 2e tentative
+	MaxDelay     time.Duration // plafond du délai entre deux tentatives
+	Multiplier   float64       // facteur appliqué au délai après chaque échec
+	// AttemptTimeout borne chaque tentative séparément (0 = pas de limite) ;
+	// une tentative expirée est réessayée comme une erreur réseau.
+	AttemptTimeout time.Duration
+}
+
+// DefaultRetryPolicy : 3 tentatives de 2 min au plus, 2s puis 4s d'attente.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialDelay:   2 * time.Second,
+		MaxDelay:       30 * time.Second,
+		Multiplier:     2,
+		AttemptTimeout: 2 * time.Minute,
+	}
+}
+
+// Delay retourne l'attente avant la tentative `attempt` (2 = premier retry).
+// Les erreurs HTTP 429 doublent le délai : YouTube pénalise les relances trop rapides.
+func (p RetryPolicy) Delay(attempt int, kind ErrorKind) time.Duration {
+	if attempt < 2 || p.InitialDelay <= 0 {
+		return 0
+	}
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+	d := float64(p.InitialDelay)
+	for i := 2; i < attempt; i++ {
+		d *= mult
+	}
+	if kind == KindThrottled {
+		d *= 2
+	}
+	delay := time.Duration(d)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// ExtractRawWithRetry appelle client.ExtractRaw en réessayant uniquement les erreurs
+// transitoires. onRetry (optionnel) est appelé avant chaque attente.
+// Chaque tentative a son propre délai (policy.AttemptTimeout) ; l'attente
+// respecte l'annulation du ctx.
+func ExtractRawWithRetry(
+	ctx context.Context,
+	client Interface,
+	url string,
+	policy RetryPolicy,
+	onRetry func(attempt int, delay time.Duration, err *ExtractError),
+) (*ExtractedRaw, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		raw, err := extractAttempt(ctx, client, url, policy.AttemptTimeout)
+		if err == nil {
+			return raw, nil
+		}
+		lastErr = err
+
+		// erreur non classée ou définitive -> abandon immédiat
+		var xerr *ExtractError
+		if !errors.As(err, &xerr) || !xerr.Transient() || attempt == attempts {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		delay := policy.Delay(attempt+1, xerr.Kind)
+		if onRetry != nil {
+			onRetry(attempt+1, delay, xerr)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("retry interrompu: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+	return nil, lastErr
+}
+
+// extractAttempt lance une tentative bornée par timeout (0 = pas de limite).
+// Une tentative expirée alors que ctx est encore valide devient une
+// *ExtractError KindNetwork, donc réessayée.
+func extractAttempt(ctx context.Context, client Interface, url string, timeout time.Duration) (*ExtractedRaw, error) {
+	if timeout <= 0 {
+		return client.ExtractRaw(ctx, url)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	raw, err := client.ExtractRaw(attemptCtx, url)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return nil, &ExtractError{
+			Kind:   KindNetwork,
+			Detail: fmt.Sprintf("tentative expirée après %s", timeout),
+			Err:    err,
+		}
+	}
+	return raw, err
+}
@@ -0,0 +1,90 @@
+package yt
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Level est le niveau d'un message émis par yt-dlp sur stderr.
+type Level int
+
+const (
+	LevelInfo    Level = iota // ligne sans préfixe connu (progression, infos)
+	LevelDebug                // "[debug] ..." (avec --verbose)
+	LevelWarning              // "WARNING: ..."
+	LevelError                // "ERROR: ..."
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Message est une ligne de stderr de yt-dlp, structurée.
+// Exemple : "WARNING: [youtube] abc: nsig extraction failed"
+//
+//	-> Level=LevelWarning, Extractor="youtube", Text="abc: nsig extraction failed"
+type Message struct {
+	Level     Level
+	Extractor string // contenu du premier [..] (ex: "youtube", "debug", "info"), peut être vide
+	Text      string
+}
+
+func (m Message) String() string {
+	if m.Extractor != "" {
+		return fmt.Sprintf("[%s] %s", m.Extractor, m.Text)
+	}
+	return m.Text
+}
+
+// reExtractor capture un préfixe "[extracteur] " en début de texte.
+var reExtractor = regexp.MustCompile(`^\[([^\]]+)\]\s*`)
+
+// ParseStderrLine transforme une ligne brute de stderr en Message.
+func ParseStderrLine(line string) Message {
+	line = strings.TrimSpace(line)
+	msg := Message{Level: LevelInfo}
+
+	switch {
+	case strings.HasPrefix(line, "ERROR:"):
+		msg.Level = LevelError
+		line = strings.TrimSpace(strings.TrimPrefix(line, "ERROR:"))
+	case strings.HasPrefix(line, "WARNING:"):
+		msg.Level = LevelWarning
+		line = strings.TrimSpace(strings.TrimPrefix(line, "WARNING:"))
+	case strings.HasPrefix(line, "[debug]"):
+		msg.Level = LevelDebug
+	}
+
+	if m := reExtractor.FindStringSubmatch(line); m != nil {
+		msg.Extractor = m[1]
+		line = line[len(m[0]):]
+	}
+	msg.Text = line
+	return msg
+}
+
+// scanLinesOrCR est un bufio.SplitFunc qui découpe sur '\n' ET '\r' :
+// yt-dlp réécrit ses lignes de progression avec '\r'.
+func scanLinesOrCR(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,43 @@
+package yt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStderrLine(t *testing.T) {
+	tests := []struct {
+		in        string
+		level     Level
+		extractor string
+		text      string
+	}{
+		{"WARNING: [youtube] abc: nsig extraction failed", LevelWarning, "youtube", "abc: nsig extraction failed"},
+		{"ERROR: [youtube] abc: Private video", LevelError, "youtube", "abc: Private video"},
+		{"[debug] Command-line config: ['-j']", LevelDebug, "debug", "Command-line config: ['-j']"},
+		{"[youtube] Extracting URL: https://youtu.be/abc", LevelInfo, "youtube", "Extracting URL: https://youtu.be/abc"},
+		{"{not json, just a brace}", LevelInfo, "", "{not json, just a brace}"},
+	}
+	for _, tc := range tests {
+		m := ParseStderrLine(tc.in)
+		if m.Level != tc.level || m.Extractor != tc.extractor || m.Text != tc.text {
+			t.Errorf("ParseStderrLine(%q) = %+v; want level=%s extractor=%q text=%q",
+				tc.in, m, tc.level, tc.extractor, tc.text)
+		}
+	}
+}
+
+func TestDecodeJSONEntries(t *testing.T) {
+	// multi-lignes + plusieurs entrées : les deux cas que l'ancien filtre "{" cassait
+	in := "{\n  \"id\": \"a\"\n}\n{\"id\": \"b\"}\n"
+	entries, err := decodeJSONEntries(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if !strings.Contains(string(entries[1]), `"b"`) {
+		t.Fatalf("second entry = %s", entries[1])
+	}
+}
@@ -9,6 +9,22 @@ type ytdlpChapter struct {
 	StartTime float64 `json:"start_time"` // champ moderne, à préférer
 	Start     float64 `json:"start"`      // fallback
 	Title     string  `json:"title"`
+	EndTime   float64 `json:"end_time"`
+}
+type ytdlpHeatmapPoint struct {
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+	Value     float64 `json:"value"`
+}
+type ytdlpComment struct {
+	ID               string `json:"id"`
+	Text             string `json:"text"`
+	Author           string `json:"author"`
+	LikeCount        int64  `json:"like_count"`
+	Timestamp        int64  `json:"timestamp"` // en Unix epoch
+	Parent           string `json:"parent"`    // "root" pour un commentaire de premier niveau
+	IsPinned         bool   `json:"is_pinned"`
+	AuthorIsUploader bool   `json:"author_is_uploader"`
 }
 type subtitleItem struct {
 	Ext string `json:"ext"`
@@ -31,15 +47,23 @@ type ytdlpOutput struct {
 	Categories        []string                  `json:"categories"`
 	YtTags            []string                  `json:"tags"`
 	Description       string                    `json:"description"`
+	Language          string                    `json:"language"` // langue originale déclarée, peut être vide
+	Duration          float64                   `json:"duration"` // en secondes, 0 si inconnue (live)
 	Chapters          []ytdlpChapter            `json:"chapters"`
 	Subtitles         map[string][]subtitleItem `json:"subtitles"`
 	AutomaticCaptions map[string][]subtitleItem `json:"automatic_captions"`
+	Comments          []ytdlpComment            `json:"comments"` // présent avec --write-comments
+	Heatmap           []ytdlpHeatmapPoint       `json:"heatmap"`
 }
 
-// ExtractedRaw contient le JSON raw, une liste de lignes d'avertissements
+// ExtractedRaw contient le JSON raw et les messages structurés de stderr.
+// yt-dlp écrit une entrée JSON par vidéo sur stdout (plusieurs pour une playlist) :
+// JSON est la première entrée, Entries les contient toutes.
 type ExtractedRaw struct {
 	JSON     []byte
-	Warnings []string
+	Entries  [][]byte
+	Warnings []Message // messages de niveau warning ou error
+	Log      []Message // tous les messages de stderr, dans l'ordre
 }
 
 // PrettyJSON retourne un json indenté
@@ -58,15 +82,18 @@ func (v *ExtractedRaw) PrintWarnings() {
 	}
 	fmt.Println("⚠️  Avertissements yt-dlp :")
 	for _, w := range v.Warnings {
-		fmt.Printf("  - %s\n", w)
+		fmt.Printf("  - %s: %s\n", w.Level, w)
 	}
 }
 
 // YtDlp représente la commande yt-dlp à exécuter (nom de binaire ou chemin) + args.
+// OnMessage (optionnel) reçoit chaque ligne de stderr dès qu'elle est émise,
+// ce qui permet d'afficher la progression en direct.
 type YtDlp struct {
-	Name   string
-	Path   string // chemin vers l'exe
-	Config YtDlpConfig
+	Name      string
+	Path      string // chemin vers l'exe
+	Config    YtDlpConfig
+	OnMessage func(Message)
 }
 
 func (y YtDlp) DisplayInfo() {
@@ -1,11 +1,15 @@
 package yt
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -43,7 +47,11 @@ func (y *YtDlp) CheckBinary() error {
 }
 
 // ExtractRaw exécute `yt-dlp -j <url>` et renvoie la sortie JSON brute.
-// La sortie est validée comme JSON avant d'être renvoyée.
+// stdout et stderr sont lus séparément :
+//   - stdout passe dans un json.Decoder (une ou plusieurs entrées JSON) ;
+//   - stderr est découpé en Message structurés, transmis en direct à OnMessage.
+//
+// En cas d'échec, l'erreur est une *ExtractError classée depuis stderr.
 func (y *YtDlp) ExtractRaw(ctx context.Context, url string) (*ExtractedRaw, error) {
 	start := time.Now()
 	defer func() {
@@ -59,29 +67,92 @@ func (y *YtDlp) ExtractRaw(ctx context.Context, url string) (*ExtractedRaw, erro
 	}
 
 	cmd := exec.CommandContext(ctx, exe, args...)
-	out, err := cmd.CombinedOutput()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, fmt.Errorf("yt-dlp dump json failed: %w, output: %s", err, string(out))
+		return nil, fmt.Errorf("yt-dlp stdout pipe: %w", err)
 	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("yt-dlp start: %w", err)
+	}
+
+	// stderr est lu en parallèle : sinon yt-dlp peut bloquer sur un pipe plein
+	var (
+		stderrText strings.Builder
+		log        []Message
+		wg         sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sc := bufio.NewScanner(stderr)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		sc.Split(scanLinesOrCR)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" {
+				continue
+			}
+			stderrText.WriteString(line)
+			stderrText.WriteByte('\n')
+			msg := ParseStderrLine(line)
+			log = append(log, msg)
+			if y.OnMessage != nil {
+				y.OnMessage(msg)
+			}
+		}
+	}()
 
-	var jsonLine string
-	var warnings []string
-	for _, line := range strings.Split(string(out), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	entries, decodeErr := decodeJSONEntries(stdout)
+	// vider stdout si le décodage s'est arrêté en route, avant Wait()
+	_, _ = io.Copy(io.Discard, stdout)
+	wg.Wait()
+	waitErr := cmd.Wait()
+
+	if waitErr != nil {
+		// erreur d'annulation/timeout : pas de classification, on la remonte telle quelle
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("yt-dlp dump json interrompu: %w", ctx.Err())
 		}
-		if strings.HasPrefix(line, "{") || strings.HasPrefix(line, "[") {
-			jsonLine = line // si plusieurs lignes JSON, prendre la dernière/ la première selon besoin
-		} else {
-			warnings = append(warnings, line)
+		return nil, newExtractError(stderrText.String(), waitErr)
+	}
+	if decodeErr != nil {
+		return nil, newExtractError(stderrText.String(), fmt.Errorf("décodage JSON de stdout: %w", decodeErr))
+	}
+	if len(entries) == 0 {
+		return nil, newExtractError(stderrText.String(), fmt.Errorf("aucun JSON détecté dans la sortie"))
+	}
+
+	raw := &ExtractedRaw{
+		JSON:    entries[0],
+		Entries: entries,
+		Log:     log,
+	}
+	for _, m := range log {
+		if m.Level >= LevelWarning {
+			raw.Warnings = append(raw.Warnings, m)
 		}
 	}
-	if jsonLine == "" {
-		return nil, fmt.Errorf("aucun JSON détecté dans la sortie: %s", string(out))
+	return raw, nil
+}
+
+// decodeJSONEntries décode toutes les valeurs JSON successives de r
+// (yt-dlp -j écrit une valeur par vidéo), quel que soit leur découpage en lignes.
+func decodeJSONEntries(r io.Reader) ([][]byte, error) {
+	dec := json.NewDecoder(r)
+	var entries [][]byte
+	for {
+		var v json.RawMessage
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, []byte(v))
 	}
-	return &ExtractedRaw{
-		JSON:     []byte(jsonLine),
-		Warnings: warnings,
-	}, nil
 }
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Seuils de WER (taux d'erreur de mots) des captions auto face aux sous-titres manuels.
+const (
+	// en dessous, la piste "manuelle" est très probablement l'ASR ré-importée
+	ReuploadWERThreshold = 0.05
+	goodWERThreshold     = 0.15
+	fairWERThreshold     = 0.30
+)
+
+// ASRQuality résume la comparaison des captions auto (hypothèse) avec les
+// sous-titres manuels (référence) d'une même vidéo.
+type ASRQuality struct {
+	Lang          string  `json:"lang"`
+	RefWords      int     `json:"ref_words"` // mots des sous-titres manuels
+	HypWords      int     `json:"hyp_words"` // mots des captions auto
+	Substitutions int     `json:"substitutions"`
+	Deletions     int     `json:"deletions"`  // mots manuels absents des captions
+	Insertions    int     `json:"insertions"` // mots des captions absents des sous-titres manuels
+	WER           float64 `json:"wer"`        // (S + D + I) / RefWords
+}
+
+// LikelyReupload : les sous-titres manuels ne sont sans doute qu'une copie de l'ASR.
+func (q ASRQuality) LikelyReupload() bool {
+	return q.RefWords > 0 && q.WER <= ReuploadWERThreshold
+}
+
+// WERPercent : WER en pourcentage, ex: "12,3 %".
+func (q ASRQuality) WERPercent() string {
+	return strings.Replace(fmt.Sprintf("%.1f %%", q.WER*100), ".", ",", 1)
+}
+
+// Verdict : appréciation de l'ASR en une ligne.
+func (q ASRQuality) Verdict() string {
+	switch {
+	case q.RefWords == 0:
+		return "comparaison impossible (sous-titres manuels vides)"
+	case q.LikelyReupload():
+		return "sous-titres manuels quasi identiques aux captions auto (ASR ré-importée ?)"
+	case q.WER <= goodWERThreshold:
+		return "captions auto fiables"
+	case q.WER <= fairWERThreshold:
+		return "captions auto correctes"
+	default:
+		return "captions auto peu fiables"
+	}
+}
+
+// Recommendation : réglage de prefer_manual_subs conseillé pour la chaîne.
+func (q ASRQuality) Recommendation() string {
+	switch {
+	case q.RefWords == 0:
+		return ""
+	case q.LikelyReupload():
+		return "prefer_manual_subs n'apporte rien sur cette chaîne : les captions auto ont des timings par mot réels"
+	case q.WER <= goodWERThreshold:
+		return "prefer_manual_subs: false possible sur cette chaîne"
+	default:
+		return "garder prefer_manual_subs: true sur cette chaîne"
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Chapitres listés dans la description ("00:00 Intro", "[12:34] - Setup",
+// "Setup (12:34)"...), que yt-dlp ne renvoie pas toujours dans "chapters".
+
+// minDescriptionChapters : en dessous, une liste de timestamps n'est pas une table des chapitres.
+const minDescriptionChapters = 2
+
+const tsPattern = `(\d{1,2}(?::\d{2}){1,2})`
+
+var (
+	// timestamp en tête de ligne, éventuellement puce, crochets ou plage "0:00 - 1:30"
+	reLeadingChapter = regexp.MustCompile(`^[^\p{L}\p{N}\[(]*[\[(]?` + tsPattern + `[\])]?` +
+		`(?:\s*[-–—]\s*[\[(]?\d{1,2}(?::\d{2}){1,2}[\])]?)?` + `(?:\s+|\s*[-–—:|•·.)]\s*)(.+)$`)
+	// timestamp en fin de ligne : "Setup - 12:34", "Setup (12:34)"
+	reTrailingChapter = regexp.MustCompile(`^[^\p{L}\p{N}]*(.+?)\s*[-–—:|]?\s*[\[(]?` + tsPattern + `[\])]?\s*$`)
+)
+
+// ParseDescriptionChapters lit les chapitres d'une description : le premier
+// bloc de lignes à timestamp (lignes vides tolérées) qui commence à 0:00, en
+// compte au moins deux et dont les timestamps croissent strictement, comme
+// l'exige YouTube. Un bloc non monotone est rejeté au profit du suivant.
+func ParseDescriptionChapters(description string) []Chapter {
+	var block []Chapter
+	flush := func() []Chapter {
+		defer func() { block = nil }()
+		if len(block) < minDescriptionChapters || block[0].Start != 0 {
+			return nil
+		}
+		for i := 1; i < len(block); i++ {
+			if block[i].Start <= block[i-1].Start {
+				return nil
+			}
+		}
+		return block
+	}
+	for line := range strings.Lines(description + "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if c, ok := parseChapterLine(line); ok {
+			block = append(block, c)
+			continue
+		}
+		if chapters := flush(); chapters != nil {
+			return chapters
+		}
+	}
+	return flush()
+}
+
+// parseChapterLine lit "timestamp titre" ou "titre timestamp".
+func parseChapterLine(line string) (Chapter, bool) {
+	ts, title := "", ""
+	if m := reLeadingChapter.FindStringSubmatch(line); m != nil {
+		ts, title = m[1], m[2]
+	} else if m := reTrailingChapter.FindStringSubmatch(line); m != nil {
+		ts, title = m[2], m[1]
+	} else {
+		return Chapter{}, false
+	}
+	start, ok := ParseTimestamp(ts)
+	title = strings.Trim(title, " \t-–—:|•·")
+	if !ok || title == "" {
+		return Chapter{}, false
+	}
+	return Chapter{Start: start, Title: title, Source: ChapterSourceDescription}, true
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseDescriptionChapters(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"formats mélangés", "Mon setup complet !\n\nChapitres :\n00:00 Intro\n[01:30] - Installation\n• 12:34 – Configuration\n\n1:02:03 | Conclusion\nMerci d'avoir regardé",
+			"[0 Intro] [90 Installation] [754 Configuration] [3723 Conclusion]"},
+		{"timestamp en fin de ligne", "Intro - 0:00\nSetup (2:05)\nDemo 10:00", "[0 Intro] [125 Setup] [600 Demo]"},
+		{"plages", "0:00 - 1:30 Intro\n1:30 - 4:00 Setup", "[0 Intro] [90 Setup]"},
+		{"non monotone rejeté", "0:00 Intro\n5:00 Setup\n3:00 Demo", "[]"},
+		{"bloc suivant retenu", "Vu à 12:30 et 14:00\nsuite\n0:00 Intro\n4:00 Demo", "[0 Intro] [240 Demo]"},
+		{"ne commence pas à 0:00", "2:00 Setup\n5:00 Demo", "[]"},
+		{"un seul timestamp", "0:00 Intro\nÀ bientôt", "[]"},
+		{"timestamp sans titre", "0:00\n1:00", "[]"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := "["
+			for i, c := range ParseDescriptionChapters(tc.description) {
+				if !c.FromDescription() {
+					t.Errorf("chapitre %q sans source description", c.Title)
+				}
+				if i > 0 {
+					got += " ["
+				}
+				got += fmt.Sprintf("%d %s]", c.Start, c.Title)
+			}
+			if got == "[" {
+				got = "[]"
+			}
+			if got != tc.want {
+				t.Errorf("ParseDescriptionChapters = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comment représente un commentaire YouTube extrait par yt-dlp (--write-comments).
+type Comment struct {
+	ID         string    `json:"id"`
+	ParentID   string    `json:"parent_id,omitempty"` // vide pour un commentaire de premier niveau
+	Author     string    `json:"author"`
+	Text       string    `json:"text"`
+	Likes      int64     `json:"likes"`
+	Timestamp  time.Time `json:"timestamp,omitzero"`
+	Pinned     bool      `json:"pinned,omitempty"`
+	ByUploader bool      `json:"by_uploader,omitempty"`
+	Mentions   []Seconds `json:"mentions,omitempty"` // timestamps cités dans le texte (ex: "12:34")
+}
+
+// IsReply indique si le commentaire est une réponse à un autre commentaire.
+func (c Comment) IsReply() bool {
+	return c.ParentID != ""
+}
+
+// reTimestamp : h:mm:ss ou m:ss / mm:ss. L'isolement (pas au milieu d'un nombre)
+// est vérifié par timestampIndexes, RE2 n'ayant pas de lookaround.
+var reTimestamp = regexp.MustCompile(`\d{1,2}(?::\d{2}){1,2}`)
+
+// timestampIndexes retourne les positions [début, fin) des timestamps isolés de text.
+func timestampIndexes(text string) [][2]int {
+	isPart := func(b byte) bool { return (b >= '0' && b <= '9') || b == ':' }
+	var out [][2]int
+	for _, m := range reTimestamp.FindAllStringIndex(text, -1) {
+		if m[0] > 0 && isPart(text[m[0]-1]) {
+			continue
+		}
+		if m[1] < len(text) && isPart(text[m[1]]) {
+			continue
+		}
+		out = append(out, [2]int{m[0], m[1]})
+	}
+	return out
+}
+
+// ParseTimestamp convertit "1:02:03", "12:34" ou "2:05" en Seconds.
+// Les minutes/secondes doivent être < 60 quand une composante supérieure existe.
+func ParseTimestamp(s string) (Seconds, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var total int64
+	for i, p := range parts {
+		if p == "" || len(p) > 2 {
+			return 0, false
+		}
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		// toute composante après la première est bornée à 59
+		if i > 0 && n >= 60 {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	return Seconds(total), true
+}
+
+// FindTimestamps retourne les timestamps mentionnés dans un texte, dans l'ordre d'apparition.
+func FindTimestamps(text string) []Seconds {
+	idx := timestampIndexes(text)
+	if len(idx) == 0 {
+		return nil
+	}
+	out := make([]Seconds, 0, len(idx))
+	for _, m := range idx {
+		if s, ok := ParseTimestamp(text[m[0]:m[1]]); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ReplaceTimestamps remplace chaque timestamp trouvé dans text par fn(match, secondes).
+// Utile pour transformer "voir 12:34" en lien cliquable.
+func ReplaceTimestamps(text string, fn func(match string, s Seconds) string) string {
+	idx := timestampIndexes(text)
+	if len(idx) == 0 {
+		return text
+	}
+	var b strings.Builder
+	prev := 0
+	for _, m := range idx {
+		start, end := m[0], m[1]
+		raw := text[start:end]
+		s, ok := ParseTimestamp(raw)
+		if !ok {
+			continue
+		}
+		b.WriteString(text[prev:start])
+		b.WriteString(fn(raw, s))
+		prev = end
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
+
+// TopComments retourne au plus n commentaires de premier niveau, triés par
+// nombre de "j'aime" décroissant (les commentaires épinglés passent devant).
+// n <= 0 => tous.
+func TopComments(comments []Comment, n int) []Comment {
+	out := make([]Comment, 0, len(comments))
+	for _, c := range comments {
+		if !c.IsReply() {
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Pinned != out[j].Pinned {
+			return out[i].Pinned
+		}
+		return out[i].Likes > out[j].Likes
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFindTimestamps(t *testing.T) {
+	got := FindTimestamps("great part at 1:02:03, also 12:34 and 2:05 5:00 but not 123:45 or 3:7")
+	want := []Seconds{3723, 754, 125, 300}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestTopComments(t *testing.T) {
+	cs := []Comment{
+		{ID: "a", Likes: 3},
+		{ID: "b", Likes: 10},
+		{ID: "r", Likes: 50, ParentID: "a"},
+		{ID: "p", Likes: 1, Pinned: true},
+	}
+	got := TopComments(cs, 2)
+	if len(got) != 2 || got[0].ID != "p" || got[1].ID != "b" {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestCommentJSONNoDate(t *testing.T) {
+	// un commentaire sans date n'écrit pas "0001-01-01T00:00:00Z"
+	b, err := json.Marshal(Comment{ID: "a", Text: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "timestamp") {
+		t.Errorf("timestamp zéro sérialisé : %s", b)
+	}
+}
@@ -0,0 +1,82 @@
+package model
+
+import "sort"
+
+// HeatmapPoint est un point du graphe "les plus revus" de YouTube.
+// Value est normalisée entre 0 et 1 par YouTube.
+type HeatmapPoint struct {
+	StartMs int64   `json:"start_ms"`
+	EndMs   int64   `json:"end_ms"`
+	Value   float64 `json:"value"`
+}
+
+// CenterMs retourne le milieu de l'intervalle du point.
+func (h HeatmapPoint) CenterMs() int64 {
+	return h.StartMs + (h.EndMs-h.StartMs)/2
+}
+
+// KeyMoment est un pic du heatmap rattaché au transcript et aux chapitres.
+type KeyMoment struct {
+	Peak         HeatmapPoint `json:"peak"`
+	TimestampMs  int64        `json:"timestamp_ms"`      // début de la phrase la plus proche du pic
+	Quote        string       `json:"quote,omitempty"`   // phrase citée
+	Chapter      string       `json:"chapter,omitempty"` // titre du chapitre contenant TimestampMs
+	ChapterIndex int          `json:"chapter_index"`     // -1 si aucun chapitre (0 est le premier)
+}
+
+// Start retourne le timestamp du moment en Seconds (pratique pour les liens).
+func (k KeyMoment) Start() Seconds {
+	return Seconds(k.TimestampMs / 1000)
+}
+
+// HeatmapPeaks retourne au plus n pics du heatmap, triés chronologiquement.
+// Un pic est un maximum local ; on prend les plus hauts en imposant un écart
+// minimal de minGapMs entre deux pics retenus. Le tout premier point est ignoré :
+// le début de la vidéo est toujours très vu et n'indique rien sur le contenu.
+func HeatmapPeaks(points []HeatmapPoint, n int, minGapMs int64) []HeatmapPoint {
+	if n <= 0 || len(points) < 2 {
+		return nil
+	}
+
+	var candidates []HeatmapPoint
+	for i := 1; i < len(points); i++ {
+		v := points[i].Value
+		if v <= 0 {
+			continue
+		}
+		if v < points[i-1].Value {
+			continue
+		}
+		if i+1 < len(points) && v < points[i+1].Value {
+			continue
+		}
+		candidates = append(candidates, points[i])
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Value > candidates[j].Value })
+
+	var out []HeatmapPoint
+	for _, c := range candidates {
+		tooClose := false
+		for _, p := range out {
+			d := c.CenterMs() - p.CenterMs()
+			if d < 0 {
+				d = -d
+			}
+			if d < minGapMs {
+				tooClose = true
+				break
+			}
+		}
+		if tooClose {
+			continue
+		}
+		out = append(out, c)
+		if len(out) == n {
+			break
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].StartMs < out[j].StartMs })
+	return out
+}
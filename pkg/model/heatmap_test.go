@@ -0,0 +1,20 @@
+package model
+
+import "testing"
+
+func TestHeatmapPeaks(t *testing.T) {
+	// points de 10s : pics à 30s (0.9), 40s (0.8, trop proche), 90s (0.7)
+	values := []float64{1, 0.2, 0.5, 0.9, 0.8, 0.3, 0.2, 0.4, 0.6, 0.7, 0.1}
+	pts := make([]HeatmapPoint, len(values))
+	for i, v := range values {
+		pts[i] = HeatmapPoint{StartMs: int64(i) * 10000, EndMs: int64(i+1) * 10000, Value: v}
+	}
+
+	got := HeatmapPeaks(pts, 3, 30000)
+	if len(got) != 2 {
+		t.Fatalf("got %d peaks, want 2: %+v", len(got), got)
+	}
+	if got[0].StartMs != 30000 || got[1].StartMs != 90000 {
+		t.Fatalf("unexpected peaks: %+v", got)
+	}
+}
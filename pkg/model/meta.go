@@ -28,24 +28,92 @@ func (s SubSource) String() string {
 	}
 }
 
+// ChapterSource : provenance d'un chapitre.
+type ChapterSource string
+
+const (
+	ChapterSourceVideo       ChapterSource = ""            // chapitres de la vidéo (yt-dlp)
+	ChapterSourceDescription ChapterSource = "description" // timestamps de la description (ParseDescriptionChapters)
+	ChapterSourceGenerated   ChapterSource = "generated"   // segmentation du transcript (subtitles.GenerateChapters)
+)
+
 // Chapter représente un chapitre d'une vidéo avec un timestamp et un titre.
 type Chapter struct {
-	Start Seconds `json:"start"`
-	Title string  `json:"title"`
+	Start  Seconds       `json:"start"`
+	End    Seconds       `json:"end,omitempty"` // 0 si inconnue (voir FillChapterEnds)
+	Title  string        `json:"title"`
+	Source ChapterSource `json:"source,omitempty"`
+}
+
+// HasEnd indique si la fin du chapitre est connue.
+func (c Chapter) HasEnd() bool {
+	return c.End > c.Start
+}
+
+// Duration retourne la durée du chapitre (0 si la fin est inconnue).
+func (c Chapter) Duration() Seconds {
+	if !c.HasEnd() {
+		return 0
+	}
+	return c.End - c.Start
+}
+
+// FillChapterEnds complète les fins manquantes : début du chapitre suivant,
+// durée de la vidéo pour le dernier (laissée à 0 si la durée est inconnue).
+// Les chapitres doivent être triés.
+func FillChapterEnds(chapters []Chapter, duration Seconds) {
+	for i := range chapters {
+		if chapters[i].HasEnd() {
+			continue
+		}
+		switch {
+		case i+1 < len(chapters):
+			chapters[i].End = chapters[i+1].Start
+		case duration > chapters[i].Start:
+			chapters[i].End = duration
+		}
+	}
+}
+
+// Generated : chapitre déduit du transcript, absent de la vidéo.
+func (c Chapter) Generated() bool {
+	return c.Source == ChapterSourceGenerated
+}
+
+// FromDescription : chapitre lu dans la description, ignoré par yt-dlp.
+func (c Chapter) FromDescription() bool {
+	return c.Source == ChapterSourceDescription
 }
 
 // SubtitleTrack décrit une piste de sous-titres associée à une vidéo.
+// Translated est vrai pour une traduction automatique de YouTube (paramètre tlang) ;
+// SourceLang est alors la langue d'origine de la piste traduite.
 type SubtitleTrack struct {
-	Lang   string    `json:"lang"`
-	Format Format    `json:"format,omitempty"`
-	URL    string    `json:"url,omitempty"`
-	Source SubSource `json:"source,omitempty"`
+	Lang       string    `json:"lang"`
+	Format     Format    `json:"format,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	Source     SubSource `json:"source,omitempty"`
+	Translated bool      `json:"translated,omitempty"`
+	SourceLang string    `json:"source_lang,omitempty"`
 }
 
 func (s SubtitleTrack) String() string {
+	if s.Translated {
+		return fmt.Sprintf("SubtitleTrack(lang=%s, format=%s, source=%s, translated from %s)",
+			s.Lang, s.Format, s.Source, s.SourceLang)
+	}
 	return fmt.Sprintf("SubtitleTrack(lang=%s, format=%s, source=%s)", s.Lang, s.Format, s.Source)
 }
 
+// BaseLang retourne la langue sans région ni suffixe : "en-US" -> "en", "fr-orig" -> "fr".
+func BaseLang(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
+
 // Meta regroupe les métadonnées extraites d'une vidéo YouTube.
 type Meta struct {
 	ID          string          `json:"id"`
@@ -55,9 +123,21 @@ type Meta struct {
 	Categories  []string        `json:"categories,omitempty"`
 	YtTags      []string        `json:"yt_tags,omitempty"`
 	Description string          `json:"description,omitempty"`
+	Language    string          `json:"language,omitempty"` // langue originale déclarée (yt-dlp "language")
+	Duration    Seconds         `json:"duration,omitempty"` // 0 si inconnue
 	Chapters    []Chapter       `json:"chapters,omitempty"`
 	AutoSubs    []SubtitleTrack `json:"subtitles,omitempty"`
 	ManualSubs  []SubtitleTrack `json:"manual_subtitles,omitempty"`
+	// TranslatedSubs : traductions automatiques proposées par YouTube (non utilisées par défaut)
+	TranslatedSubs []SubtitleTrack `json:"translated_subtitles,omitempty"`
+	Comments       []Comment       `json:"comments,omitempty"`
+	Segments       []Segment       `json:"sponsor_segments,omitempty"` // SponsorBlock (hors yt-dlp)
+	Heatmap        []HeatmapPoint  `json:"heatmap,omitempty"`          // "les plus revus"
+}
+
+// WatchURL retourne l'URL YouTube de la vidéo.
+func (m Meta) WatchURL() string {
+	return "https://www.youtube.com/watch?v=" + m.ID
 }
 
 func (m Meta) HasManualSubs() bool {
@@ -68,10 +148,24 @@ func (m Meta) HasAutoSubs() bool {
 	return len(m.AutoSubs) != 0
 }
 
+// OriginalLang retourne la langue originale de la vidéo : le champ "language"
+// de yt-dlp, sinon la langue de la piste automatique "-orig". "" si inconnue.
+func (m Meta) OriginalLang() string {
+	if m.Language != "" {
+		return m.Language
+	}
+	for _, t := range m.AutoSubs {
+		if t.Lang != "" {
+			return t.Lang
+		}
+	}
+	return ""
+}
+
 func (m Meta) String() string {
-	return fmt.Sprintf("Meta[ID=%s, Title=%q, Uploader=%s, Date=%s, Chapters=%d, Subtitles=%d]",
+	return fmt.Sprintf("Meta[ID=%s, Title=%q, Uploader=%s, Date=%s, Chapters=%d, Subtitles=%d, Comments=%d]",
 		m.ID, m.Title, m.Uploader, m.UploadDate.Format("2006-01-02"),
-		len(m.Chapters), len(m.AutoSubs)+len(m.ManualSubs))
+		len(m.Chapters), len(m.AutoSubs)+len(m.ManualSubs), len(m.Comments))
 }
 
 // Pretty retourne une fiche multi-lignes simple.
@@ -109,7 +203,9 @@ func (m Meta) Pretty() string {
 			"  Date       : %s\n"+
 			"  Chapters   : %d\n"+
 			"  AutoSubs   : %s\n"+
-			"  ManualSubs : %s\n",
+			"  ManualSubs : %s\n"+
+			"  Translated : %d\n"+
+			"  Comments   : %d\n",
 		m.ID,
 		m.Title,
 		m.Uploader,
@@ -117,5 +213,7 @@ func (m Meta) Pretty() string {
 		len(m.Chapters),
 		formatLangs(langsFrom(m.AutoSubs)),
 		formatLangs(langsFrom(m.ManualSubs)),
+		len(m.TranslatedSubs),
+		len(m.Comments),
 	)
 }
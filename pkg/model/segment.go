@@ -0,0 +1,41 @@
+package model
+
+import "fmt"
+
+// Segment représente un segment SponsorBlock (sponsor, autopromo, intro, outro...).
+// Les bornes sont en millisecondes depuis le début de la vidéo.
+type Segment struct {
+	Category   string `json:"category"`
+	StartMs    int64  `json:"start_ms"`
+	EndMs      int64  `json:"end_ms"`
+	UUID       string `json:"uuid,omitempty"`
+	ActionType string `json:"action_type,omitempty"` // "skip", "mute", ...
+}
+
+// DurationMs retourne la durée du segment (0 si bornes incohérentes).
+func (s Segment) DurationMs() int64 {
+	if s.EndMs <= s.StartMs {
+		return 0
+	}
+	return s.EndMs - s.StartMs
+}
+
+// Contains indique si ts (ms) est dans [StartMs, EndMs).
+func (s Segment) Contains(ts int64) bool {
+	return ts >= s.StartMs && ts < s.EndMs
+}
+
+func (s Segment) String() string {
+	return fmt.Sprintf("Segment(%s, %s -> %s)", s.Category,
+		Seconds(s.StartMs/1000).TimestampHHMMSS(), Seconds(s.EndMs/1000).TimestampHHMMSS())
+}
+
+// TotalSegmentsMs additionne la durée des segments.
+// Les chevauchements ne sont pas fusionnés : SponsorBlock n'en renvoie quasiment pas.
+func TotalSegmentsMs(segs []Segment) int64 {
+	var total int64
+	for _, s := range segs {
+		total += s.DurationMs()
+	}
+	return total
+}
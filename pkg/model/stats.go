@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// ReadingWPM : vitesse de lecture silencieuse supposée pour le temps de lecture.
+const ReadingWPM = 230
+
+// TranscriptStats : statistiques d'un transcript (voir subtitles.Transcript.Stats),
+// sauvegardées avec la note pour calculer les mots-clés TF-IDF de la bibliothèque.
+type TranscriptStats struct {
+	VideoID string `json:"video_id"`
+	Title   string `json:"title"`
+	Lang    string `json:"lang"`
+	Words   int    `json:"words"`
+	// DurationMs : du début de la vidéo à la fin de la dernière phrase
+	DurationMs int64 `json:"duration_ms"`
+	// SpeechMs : DurationMs moins les trous (base du débit)
+	SpeechMs int64          `json:"speech_ms"`
+	WPM      float64        `json:"wpm"` // mots par minute de parole
+	Chapters []ChapterStats `json:"chapters,omitempty"`
+	Gaps     []Gap          `json:"gaps,omitempty"`
+	TopTerms []TermCount    `json:"top_terms,omitempty"`
+	// Terms : fréquence de chaque terme (mots vides exclus), pour le TF-IDF
+	Terms map[string]int `json:"terms,omitempty"`
+	// Keywords : mots-clés TF-IDF face au reste de la bibliothèque (renseigné par l'app)
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// ChapterStats : débit d'un chapitre.
+type ChapterStats struct {
+	Title   string  `json:"title"`
+	StartMs int64   `json:"start_ms"`
+	Words   int     `json:"words"`
+	WPM     float64 `json:"wpm"`
+}
+
+// Gap : passage sans sous-titre (silence, musique, passage non transcrit).
+type Gap struct {
+	StartMs int64 `json:"start_ms"`
+	EndMs   int64 `json:"end_ms"`
+}
+
+// DurationMs : durée du trou.
+func (g Gap) DurationMs() int64 {
+	return g.EndMs - g.StartMs
+}
+
+// TermCount : un terme et son nombre d'occurrences.
+type TermCount struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// WordsPerMinute : mots par minute sur durationMs (0 si la durée est nulle).
+func WordsPerMinute(words int, durationMs int64) float64 {
+	if durationMs <= 0 {
+		return 0
+	}
+	return math.Round(float64(words)*60_000/float64(durationMs)*10) / 10
+}
+
+// ReadingTime : temps de lecture du transcript, ex: "6 min 30 s".
+func (s TranscriptStats) ReadingTime() string {
+	return Seconds(int64(s.Words) * 60 / ReadingWPM).Human()
+}
+
+// GapsMs : durée cumulée des trous.
+func (s TranscriptStats) GapsMs() int64 {
+	var total int64
+	for _, g := range s.Gaps {
+		total += g.DurationMs()
+	}
+	return total
+}
+
+// CoveragePercent : part de DurationMs couverte par des sous-titres, ex: "93 %".
+func (s TranscriptStats) CoveragePercent() string {
+	if s.DurationMs <= 0 {
+		return "0 %"
+	}
+	return fmt.Sprintf("%d %%", s.SpeechMs*100/s.DurationMs)
+}
@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Seconds est un alias explicite pour représenter une durée en secondes.
 type Seconds int64
@@ -15,21 +18,68 @@ func (s Seconds) TimestampHHMMSS() string {
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, sec)
 }
 
+// Human formate une durée lisible : "45 s", "3 min 05 s", "1 h 02 min".
+func (s Seconds) Human() string {
+	total := int64(s)
+	if total < 0 {
+		total = 0
+	}
+	h := total / 3600
+	m := (total % 3600) / 60
+	sec := total % 60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%d h %02d min", h, m)
+	case m > 0:
+		return fmt.Sprintf("%d min %02d s", m, sec)
+	default:
+		return fmt.Sprintf("%d s", sec)
+	}
+}
+
 func (s Seconds) Milliseconds() int64 {
 	return int64(s) * 1000
 }
 
+// URL ajoute le paramètre t=<secondes>s à baseURL ("" si baseURL est vide).
+func (s Seconds) URL(baseURL string) string {
+	if baseURL == "" {
+		return ""
+	}
+	sep := "?"
+	if strings.Contains(baseURL, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%st=%ds", baseURL, sep, int64(s))
+}
+
+// Link : "[HH:MM:SS](url&t=Ns)", ou "HH:MM:SS" seul si baseURL est vide.
+func (s Seconds) Link(baseURL string) string {
+	ts := s.TimestampHHMMSS()
+	if baseURL == "" {
+		return ts
+	}
+	return fmt.Sprintf("[%s](%s)", ts, s.URL(baseURL))
+}
+
 // constantes pour les formats de fichiers
 type Format string
 
 const (
 	FormatTXT      Format = "txt"
 	FormatMARKDOWN Format = "md"
+	FormatJSON     Format = "json" // export JSON du transcript (phrases + chapitres)
 	FormatJSON3    Format = "json3"
 	FormatSRT      Format = "srt"
 	FormatVTT      Format = "vtt"
+	FormatTTML     Format = "ttml"
+	FormatSRV3     Format = "srv3" // XML YouTube "timedtext format 3"
 )
 
+// SubtitleFormatPreference : formats de sous-titres acceptés, du préféré au moins bon.
+// json3 et srv3 portent les timestamps par mot ; vtt seulement en mode "karaoké".
+var SubtitleFormatPreference = []Format{FormatJSON3, FormatSRV3, FormatVTT, FormatTTML, FormatSRT}
+
 // du format en chaine à la constante de type Format, return une erreur si format inconnu
 func ParseFormat(s string) (Format, error) {
 	switch s {
@@ -39,23 +89,42 @@ func ParseFormat(s string) (Format, error) {
 		return FormatMARKDOWN, nil
 	case "json3":
 		return FormatJSON3, nil
+	case "json":
+		return FormatJSON, nil
 	case "srt":
 		return FormatSRT, nil
 	case "vtt":
 		return FormatVTT, nil
+	case "ttml":
+		return FormatTTML, nil
+	case "srv3":
+		return FormatSRV3, nil
 	default:
 		return "", fmt.Errorf("format demandé inconnu: %s", s)
 	}
 }
 
 func (f Format) IsSubtitle() bool {
-	return f == FormatJSON3 || f == FormatSRT || f == FormatVTT
+	switch f {
+	case FormatJSON3, FormatSRT, FormatVTT, FormatTTML, FormatSRV3:
+		return true
+	}
+	return false
 }
 
 func (f Format) IsTextual() bool {
 	return f == FormatTXT || f == FormatMARKDOWN
 }
 
+// IsTranscriptOutput indique si un transcript peut être écrit dans ce format.
+func (f Format) IsTranscriptOutput() bool {
+	switch f {
+	case FormatTXT, FormatMARKDOWN, FormatSRT, FormatVTT, FormatJSON:
+		return true
+	}
+	return false
+}
+
 func (f Format) Extension() string {
 	return "." + string(f)
 }